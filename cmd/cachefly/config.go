@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+)
+
+// env carries the global settings and output streams of a command run.
+type env struct {
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+
+	profile string
+	token   string
+	output  string
+
+	client *cachefly.Client
+}
+
+func (e *env) registerGlobal(fs *flag.FlagSet) {
+	fs.StringVar(&e.profile, "profile", e.profile, "config profile to use")
+	fs.StringVar(&e.token, "token", e.token, "API token")
+	fs.StringVar(&e.output, "o", e.output, "output format: table, json or yaml")
+}
+
+// parseGlobal parses global flags given before the command name and returns
+// the remaining arguments.
+func (e *env) parseGlobal(args []string) ([]string, error) {
+	fs := flag.NewFlagSet("cachefly", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	e.registerGlobal(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	return fs.Args(), nil
+}
+
+func (e *env) validate() error {
+	switch e.output {
+	case "":
+		e.output = "table"
+	case "table", "json", "yaml":
+	default:
+		return usageErrorf("unknown output format %q", e.output)
+	}
+	return nil
+}
+
+// configPath returns the config file shared with the SDK, see
+// cachefly.DefaultConfigPath.
+func (e *env) configPath() (string, error) {
+	return cachefly.DefaultConfigPath()
+}
+
+func (e *env) loadConfig() (*cachefly.ConfigFile, error) {
+	path, err := e.configPath()
+	if err != nil {
+		return nil, err
+	}
+	return cachefly.LoadConfigFile(path)
+}
+
+func (e *env) saveConfig(cfg *cachefly.ConfigFile) error {
+	path, err := e.configPath()
+	if err != nil {
+		return err
+	}
+	return cfg.Save(path)
+}
+
+// cachefly returns the API client, resolving the token from --token,
+// $CACHEFLY_API_TOKEN or the selected profile.
+func (e *env) cachefly() (*cachefly.Client, error) {
+	if e.client != nil {
+		return e.client, nil
+	}
+
+	opts := []cachefly.Option{cachefly.WithUserAgent("cachefly-cli")}
+	token := e.token
+	if token == "" {
+		token = e.getenv("CACHEFLY_API_TOKEN")
+	}
+
+	cfg, err := e.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	p, name, ok := cfg.Profile(e.profile)
+	if !ok && e.profile != "" {
+		return nil, usageErrorf("profile %q not found", name)
+	}
+	if token == "" {
+		token = p.Token
+	}
+	if p.BaseURL != "" {
+		opts = append(opts, cachefly.WithBaseURL(p.BaseURL))
+	}
+	if token == "" {
+		return nil, usageErrorf("no API token: use --token, CACHEFLY_API_TOKEN or \"cachefly config set %s --token ...\"", name)
+	}
+
+	e.client = cachefly.NewClient(append(opts, cachefly.WithToken(token))...)
+	return e.client, nil
+}
+
+func configCommand() *command {
+	return &command{
+		name:    "config",
+		summary: "Manage CLI profiles",
+		sub: []*command{
+			{
+				name: "set", args: "<profile> --token <token> [--base-url <url>]", summary: "Create or update a profile",
+				setup: func(fs *flag.FlagSet) runFunc {
+					baseURL := fs.String("base-url", "", "API base URL")
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 1, "<profile>"); err != nil {
+							return err
+						}
+						if e.token == "" {
+							return usageErrorf("--token is required")
+						}
+						cfg, err := e.loadConfig()
+						if err != nil {
+							return err
+						}
+						cfg.Profiles[args[0]] = cachefly.Profile{Token: e.token, BaseURL: *baseURL}
+						if cfg.Current == "" {
+							cfg.Current = args[0]
+						}
+						if err := e.saveConfig(cfg); err != nil {
+							return err
+						}
+						fmt.Fprintf(e.stdout, "Profile %q saved\n", args[0])
+						return nil
+					}
+				},
+			},
+			{
+				name: "use", args: "<profile>", summary: "Select the default profile",
+				setup: func(fs *flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 1, "<profile>"); err != nil {
+							return err
+						}
+						cfg, err := e.loadConfig()
+						if err != nil {
+							return err
+						}
+						if _, ok := cfg.Profiles[args[0]]; !ok {
+							return usageErrorf("profile %q not found", args[0])
+						}
+						cfg.Current = args[0]
+						return e.saveConfig(cfg)
+					}
+				},
+			},
+			{
+				name: "list", summary: "List profiles",
+				setup: func(fs *flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						cfg, err := e.loadConfig()
+						if err != nil {
+							return err
+						}
+						_, current, _ := cfg.Profile(e.profile)
+
+						type row struct {
+							Name    string `json:"name"`
+							Current bool   `json:"current"`
+							Token   string `json:"token"`
+							BaseURL string `json:"baseUrl,omitempty"`
+						}
+						var rows []row
+						for name, p := range cfg.Profiles {
+							rows = append(rows, row{Name: name, Current: name == current, Token: maskToken(p.Token), BaseURL: p.BaseURL})
+						}
+						sort.Slice(rows, func(i, j int) bool { return rows[i].Name < rows[j].Name })
+
+						return printItems(e, rows, []column[row]{
+							{"NAME", func(r row) string { return r.Name }},
+							{"CURRENT", func(r row) string { return yesNo(r.Current) }},
+							{"TOKEN", func(r row) string { return r.Token }},
+							{"BASE URL", func(r row) string { return r.BaseURL }},
+						})
+					}
+				},
+			},
+		},
+	}
+}
+
+// maskToken hides all but the last four characters of a token.
+func maskToken(token string) string {
+	if len(token) <= 4 {
+		return "****"
+	}
+	return "****" + token[len(token)-4:]
+}
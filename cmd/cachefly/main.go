@@ -0,0 +1,227 @@
+// Command cachefly is a command-line client for the CacheFly API.
+//
+// Usage:
+//
+//	cachefly [global flags] <group> <command> [flags] [args]
+//
+// Run "cachefly help" for the list of commands. The API token is taken from
+// --token, the CACHEFLY_API_TOKEN environment variable or the selected
+// profile of the config file (see "cachefly config").
+//
+// Exit codes:
+//
+//	0  success
+//	1  unexpected error
+//	2  invalid usage
+//	3  authentication or permission error (401, 403)
+//	4  resource not found (404)
+//	5  request rejected (400, 409, 422)
+//	6  rate limited (429)
+//	7  server error (5xx)
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+)
+
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+	exitAuth
+	exitNotFound
+	exitRejected
+	exitRateLimited
+	exitServer
+)
+
+// errUsage marks errors caused by invalid arguments.
+var errUsage = errors.New("usage")
+
+func usageErrorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", errUsage, fmt.Sprintf(format, args...))
+}
+
+// exitCode maps an error returned by a command to the process exit code.
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+		return exitUsage
+	case cachefly.IsUnauthorized(err), cachefly.IsForbidden(err):
+		return exitAuth
+	case cachefly.IsNotFound(err):
+		return exitNotFound
+	case cachefly.IsValidation(err), cachefly.IsConflict(err), errors.As(err, new(api.ServiceOptionsValidationError)):
+		return exitRejected
+	case cachefly.IsRateLimited(err):
+		return exitRateLimited
+	case cachefly.IsServerError(err):
+		return exitServer
+	default:
+		return exitError
+	}
+}
+
+// runFunc executes a leaf command with its positional arguments.
+type runFunc func(ctx context.Context, e *env, args []string) error
+
+// command is a node of the command tree. Leaf commands have a setup function
+// that registers their flags and returns the function running the command.
+type command struct {
+	name    string
+	args    string
+	summary string
+	setup   func(fs *flag.FlagSet) runFunc
+	sub     []*command
+}
+
+func (c *command) find(name string) *command {
+	for _, s := range c.sub {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func rootCommand() *command {
+	return &command{
+		name: "cachefly",
+		sub: []*command{
+			servicesCommand(),
+			domainsCommand(),
+			originsCommand(),
+			certsCommand(),
+			optionsCommand(),
+			statsCommand(),
+			warmCommand(),
+			usersCommand(),
+			logTargetsCommand(),
+			configCommand(),
+		},
+	}
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// run executes the command line args and returns the exit code.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	e := &env{stdout: stdout, stderr: stderr, getenv: os.Getenv}
+
+	root := rootCommand()
+	cmd, path := root, []string{root.name}
+	for cmd.setup == nil {
+		// global flags may appear before the command path
+		for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+			rest, err := e.parseGlobal(args)
+			if err != nil {
+				fmt.Fprintln(stderr, "Error:", err)
+				return exitUsage
+			}
+			args = rest
+		}
+		if len(args) == 0 || args[0] == "help" {
+			printHelp(stderr, cmd, path)
+			if len(args) == 0 && cmd != root {
+				return exitUsage
+			}
+			return exitOK
+		}
+		next := cmd.find(args[0])
+		if next == nil {
+			fmt.Fprintf(stderr, "Error: unknown command %q\n\n", strings.Join(append(path, args[0]), " "))
+			printHelp(stderr, cmd, path)
+			return exitUsage
+		}
+		cmd, path, args = next, append(path, next.name), args[1:]
+	}
+
+	fs := flag.NewFlagSet(strings.Join(path, " "), flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	e.registerGlobal(fs)
+	runCmd := cmd.setup(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s %s\n\n%s\n\nFlags:\n", strings.Join(path, " "), cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+
+	positional, err := parseInterleaved(fs, args)
+	if err == nil {
+		err = e.validate()
+	}
+	if err == nil {
+		err = runCmd(ctx, e, positional)
+	}
+
+	code := exitCode(err)
+	switch {
+	case code == exitOK:
+	case errors.Is(err, flag.ErrHelp):
+		// usage was already printed by the flag set
+	case code == exitUsage:
+		fmt.Fprintln(stderr, "Error:", strings.TrimPrefix(err.Error(), errUsage.Error()+": "))
+		fs.Usage()
+	default:
+		fmt.Fprintln(stderr, "Error:", err)
+	}
+	return code
+}
+
+// parseInterleaved parses fs allowing flags to appear after positional
+// arguments, e.g. "services get svc-1 -o json".
+func parseInterleaved(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func printHelp(w io.Writer, cmd *command, path []string) {
+	fmt.Fprintf(w, "Usage: %s <command> [flags] [args]\n\nCommands:\n", strings.Join(path, " "))
+
+	subs := append([]*command(nil), cmd.sub...)
+	if cmd.name != "cachefly" {
+		sort.Slice(subs, func(i, j int) bool { return subs[i].name < subs[j].name })
+	}
+	for _, s := range subs {
+		fmt.Fprintf(w, "  %-12s %s\n", s.name, s.summary)
+	}
+	fmt.Fprintf(w, "\nGlobal flags:\n")
+	fmt.Fprintf(w, "  --profile name   config profile to use (default %q or $CACHEFLY_PROFILE)\n", cachefly.DefaultProfile)
+	fmt.Fprintf(w, "  --token token    API token, overrides the profile and $CACHEFLY_API_TOKEN\n")
+	fmt.Fprintf(w, "  -o format        output format: table, json or yaml (default table)\n")
+}
+
+// requireArgs returns a usage error unless exactly n positional arguments were given.
+func requireArgs(args []string, n int, names string) error {
+	if len(args) != n {
+		return usageErrorf("expected %s", names)
+	}
+	return nil
+}
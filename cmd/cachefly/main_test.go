@@ -0,0 +1,371 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly/cacheflytest"
+)
+
+// runCLI runs the CLI against handler with an isolated config file.
+func runCLI(t *testing.T, handler http.HandlerFunc, args ...string) (int, string, string) {
+	t.Helper()
+	if handler != nil {
+		server := httptest.NewServer(handler)
+		t.Cleanup(server.Close)
+		t.Setenv("CACHEFLY_API_BASE_URL", server.URL+"/api/2.6")
+	}
+	t.Setenv("CACHEFLY_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("CACHEFLY_API_TOKEN", "")
+	t.Setenv("CACHEFLY_PROFILE", "")
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun_ServicesList(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/2.6/services" {
+			t.Errorf("Expected path /api/2.6/services, got %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("Expected token from --token, got %q", got)
+		}
+		w.Write([]byte(`{"meta":{"count":2},"data":[{"_id":"s1","name":"web","uniqueName":"web-1","status":"ACTIVE"},{"_id":"s2","name":"api","uniqueName":"api-1","status":"DEACTIVATED"}]}`))
+	}
+
+	code, out, errOut := runCLI(t, handler, "--token", "tok", "services", "list")
+	if code != exitOK {
+		t.Fatalf("Expected exit 0, got %d: %s", code, errOut)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[2], "api-1") {
+		t.Errorf("Unexpected table output:\n%s", out)
+	}
+
+	code, out, _ = runCLI(t, handler, "services", "list", "-o", "json", "--token", "tok")
+	if code != exitOK {
+		t.Fatalf("Expected exit 0, got %d", code)
+	}
+	var services []map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &services); err != nil || len(services) != 2 {
+		t.Errorf("Unexpected JSON output %q: %v", out, err)
+	}
+}
+
+func TestRun_ExitCodes(t *testing.T) {
+	for status, want := range map[int]int{
+		http.StatusNotFound:            exitNotFound,
+		http.StatusUnauthorized:        exitAuth,
+		http.StatusUnprocessableEntity: exitRejected,
+		http.StatusServiceUnavailable:  exitServer,
+	} {
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			w.Write([]byte(`{"message":"nope"}`))
+		}
+		code, _, errOut := runCLI(t, handler, "--token", "tok", "services", "get", "s1")
+		if code != want {
+			t.Errorf("Status %d: expected exit %d, got %d", status, want, code)
+		}
+		if !strings.Contains(errOut, "nope") {
+			t.Errorf("Status %d: expected API message on stderr, got %q", status, errOut)
+		}
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	tests := [][]string{
+		{"services", "bogus"},
+		{"services", "get"},
+		{"--token", "t", "services", "list", "-o", "xml"},
+		{"services"},
+		{"services", "list"}, // no token configured
+	}
+	for _, args := range tests {
+		if code, _, _ := runCLI(t, nil, args...); code != exitUsage {
+			t.Errorf("%v: expected exit %d, got %d", args, exitUsage, code)
+		}
+	}
+
+	if code, _, errOut := runCLI(t, nil, "help"); code != exitOK || !strings.Contains(errOut, "services") {
+		t.Errorf("Expected help to list commands, got %d: %s", code, errOut)
+	}
+}
+
+func TestRun_Profiles(t *testing.T) {
+	t.Setenv("CACHEFLY_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("CACHEFLY_API_TOKEN", "")
+	t.Setenv("CACHEFLY_PROFILE", "")
+
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.Write([]byte(`{"_id":"u1","username":"ops"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	run(context.Background(), []string{"config", "set", "prod", "--token", "prod-token", "--base-url", server.URL}, &out, &out)
+	run(context.Background(), []string{"config", "set", "staging", "--token", "staging-token", "--base-url", server.URL}, &out, &out)
+
+	if code := run(context.Background(), []string{"users", "me"}, &out, &out); code != exitOK {
+		t.Fatalf("Expected exit 0, got %d: %s", code, out.String())
+	}
+	if gotAuth != "Bearer prod-token" {
+		t.Errorf("Expected the first profile to be current, got %q", gotAuth)
+	}
+
+	run(context.Background(), []string{"--profile", "staging", "users", "me"}, &out, &out)
+	if gotAuth != "Bearer staging-token" {
+		t.Errorf("Expected --profile to select staging, got %q", gotAuth)
+	}
+
+	out.Reset()
+	run(context.Background(), []string{"config", "list"}, &out, &out)
+	if strings.Contains(out.String(), "prod-token") || !strings.Contains(out.String(), "****oken") {
+		t.Errorf("Expected masked tokens, got:\n%s", out.String())
+	}
+}
+
+func TestStatsPeriod(t *testing.T) {
+	now := time.Date(2025, 3, 10, 15, 4, 5, 0, time.UTC)
+
+	from, to, err := statsPeriod("", "", now)
+	if err != nil || !to.Equal(time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)) || !from.Equal(to.AddDate(0, 0, -7)) {
+		t.Errorf("Unexpected default period %v - %v (%v)", from, to, err)
+	}
+	if _, _, err := statsPeriod("yesterday", "", now); exitCode(err) != exitUsage {
+		t.Errorf("Expected usage error, got %v", err)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := map[error]int{
+		nil:                                 exitOK,
+		fmt.Errorf("boom"):                  exitError,
+		usageErrorf("bad"):                  exitUsage,
+		&cachefly.APIError{StatusCode: 403}: exitAuth,
+		&cachefly.APIError{StatusCode: 409}: exitRejected,
+		&cachefly.APIError{StatusCode: 429}: exitRateLimited,
+		fmt.Errorf("wrapped: %w", &cachefly.APIError{StatusCode: 404}): exitNotFound,
+	}
+	for err, want := range tests {
+		if got := exitCode(err); got != want {
+			t.Errorf("exitCode(%v) = %d, want %d", err, got, want)
+		}
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	var b bytes.Buffer
+	err := writeYAML(&b, map[string]interface{}{
+		"name":    "web",
+		"count":   3,
+		"enabled": true,
+		"empty":   "",
+		"version": "1.0",
+		"tags":    []string{"a", "b: c"},
+		"nested":  map[string]interface{}{"ttl": 60, "list": []interface{}{map[string]interface{}{"x": 1, "y": nil}}},
+		"none":    []string{},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := `count: 3
+empty: ""
+enabled: true
+name: web
+nested:
+  list:
+    - x: 1
+      "y": null
+  ttl: 60
+none: []
+tags:
+  - a
+  - "b: c"
+version: "1.0"
+`
+	if b.String() != want {
+		t.Errorf("Unexpected YAML:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestRun_Purge(t *testing.T) {
+	srv := cacheflytest.NewServer()
+	defer srv.Close()
+	svc := srv.AddService(api.Service{Name: "web", UniqueName: "web"})
+	srv.AddDomain(svc.ID, api.ServiceDomain{Name: "cdn.example.com"})
+
+	t.Setenv("CACHEFLY_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("CACHEFLY_API_BASE_URL", srv.URL)
+	t.Setenv("CACHEFLY_API_TOKEN", srv.Token)
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), []string{"services", "purge", svc.ID, "https://cdn.example.com/a.css", "/a.css", "https://elsewhere.com/b"}, &stdout, &stderr)
+	if code != exitError || !strings.Contains(stderr.String(), "Purged 2 of 3 paths in 1 requests") {
+		t.Errorf("Expected a partial purge report, got %d: %s", code, stderr.String())
+	}
+	if purges := srv.Purges(svc.ID); len(purges) != 1 || len(purges[0].Paths) != 1 || purges[0].Paths[0] != "/a.css" {
+		t.Errorf("Expected a single de-duplicated path, got %+v", purges)
+	}
+}
+
+func TestRun_OptionsValidate(t *testing.T) {
+	srv := cacheflytest.NewServer()
+	defer srv.Close()
+	svc := srv.AddService(api.Service{Name: "web", UniqueName: "web"})
+
+	t.Setenv("CACHEFLY_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("CACHEFLY_API_BASE_URL", srv.URL)
+	t.Setenv("CACHEFLY_API_TOKEN", srv.Token)
+
+	dir := t.TempDir()
+	snapshot := filepath.Join(dir, "metadata.json")
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"options", "metadata", svc.ID, "--save", snapshot}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	good := filepath.Join(dir, "good.json")
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(good, []byte(`{"cors": true, "error_ttl": 60}`), 0o644)
+	os.WriteFile(bad, []byte(`{"cors": "yes", "edgetoorigin": true}`), 0o644)
+
+	// Validation must not need the API.
+	srv.Close()
+	stdout.Reset()
+	stderr.Reset()
+	code := run(context.Background(), []string{"options", "validate", "--metadata", snapshot, good, bad}, &stdout, &stderr)
+	if code != exitRejected {
+		t.Errorf("Expected exit code %d, got %d: %s", exitRejected, code, stderr.String())
+	}
+	if stdout.String() != good+": OK\n" {
+		t.Errorf("Unexpected output %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), bad+": cors:") || !strings.Contains(stderr.String(), bad+": edgetoorigin:") {
+		t.Errorf("Expected per-option errors, got %q", stderr.String())
+	}
+}
+
+func TestRun_WarmWait(t *testing.T) {
+	srv := cacheflytest.NewServer()
+	defer srv.Close()
+	task := srv.AddCacheWarmingTask(api.CacheWarmingTask{Name: "home", Targets: []string{"https://cdn.example.com/"}})
+	srv.SetCacheWarmingStatus(task.ID, api.CacheWarmingFailed)
+
+	t.Setenv("CACHEFLY_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("CACHEFLY_API_BASE_URL", srv.URL)
+	t.Setenv("CACHEFLY_API_TOKEN", srv.Token)
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), []string{"warm", "wait", task.ID, "--interval", "1ms"}, &stdout, &stderr)
+	if code != exitError || !strings.Contains(stderr.String(), "cache warming task "+task.ID+" failed") {
+		t.Errorf("Expected the task failure to be reported, got %d: %s", code, stderr.String())
+	}
+
+	srv.SetCacheWarmingStatus(task.ID, api.CacheWarmingCompleted)
+	stdout.Reset()
+	stderr.Reset()
+	if code := run(context.Background(), []string{"warm", "wait", task.ID}, &stdout, &stderr); code != exitOK {
+		t.Errorf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "COMPLETED") {
+		t.Errorf("Expected the final task state, got %q", stdout.String())
+	}
+}
+
+func TestRun_WarmCreateFromSitemap(t *testing.T) {
+	srv := cacheflytest.NewServer()
+	defer srv.Close()
+	region := srv.AddDeliveryRegion(api.DeliveryRegion{Name: "Global"})
+
+	t.Setenv("CACHEFLY_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("CACHEFLY_API_BASE_URL", srv.URL)
+	t.Setenv("CACHEFLY_API_TOKEN", srv.Token)
+
+	sitemap := filepath.Join(t.TempDir(), "sitemap.xml")
+	os.WriteFile(sitemap, []byte(`<urlset><url><loc>https://cdn.example.com/</loc></url><url><loc>https://cdn.example.com/a</loc></url></urlset>`), 0o644)
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), []string{"warm", "create", "--sitemap", sitemap, "--per-task", "2", "--name", "site", "https://cdn.example.com/b", "-o", "json"}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	var tasks []api.CacheWarmingTask
+	if err := json.Unmarshal(stdout.Bytes(), &tasks); err != nil {
+		t.Fatalf("Expected JSON output, got %v: %s", err, stdout.String())
+	}
+	if len(tasks) != 2 || tasks[0].Name != "site (1/2)" || len(tasks[0].Targets) != 2 || tasks[1].Targets[0] != "https://cdn.example.com/a" {
+		t.Errorf("Unexpected tasks %+v", tasks)
+	}
+	if len(tasks[0].Regions) != 1 || tasks[0].Regions[0] != region.ID {
+		t.Errorf("Expected every delivery region, got %v", tasks[0].Regions)
+	}
+}
+
+func TestRun_DomainsValidate(t *testing.T) {
+	srv := cacheflytest.NewServer()
+	defer srv.Close()
+	svc := srv.AddService(api.Service{Name: "web", UniqueName: "web"})
+	d := srv.AddDomain(svc.ID, api.ServiceDomain{Name: "cdn.example.com"})
+
+	t.Setenv("CACHEFLY_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("CACHEFLY_API_BASE_URL", srv.URL)
+	t.Setenv("CACHEFLY_API_TOKEN", srv.Token)
+
+	// complete the validation once it has been requested
+	go func() {
+		for !slices.Contains(srv.Requests(), "PUT /services/"+svc.ID+"/domains/"+d.ID+"/validationReady") {
+			time.Sleep(time.Millisecond)
+		}
+		srv.SetDomainValidationStatus(d.ID, api.DomainValidationValidated)
+	}()
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), []string{"domains", "validate", svc.ID, d.ID, "--interval", "1ms"}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "http://cdn.example.com/.well-known/acme-challenge/ must be served by CacheFly through cdn-example-com.validation.cachefly.test") {
+		t.Errorf("Expected the HTTP challenge, got %q", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "VALIDATED") {
+		t.Errorf("Expected the validated domain, got %q", stdout.String())
+	}
+}
+
+func TestRun_CertsExpiring(t *testing.T) {
+	srv := cacheflytest.NewServer()
+	defer srv.Close()
+	now := time.Now().UTC()
+	srv.AddCertificate(api.Certificate{SubjectCommonName: "soon.example.com", InUse: true, NotAfter: now.AddDate(0, 0, 10).Format(time.RFC3339), Domains: []string{"soon.example.com"}})
+	srv.AddCertificate(api.Certificate{SubjectCommonName: "later.example.com", InUse: true, NotAfter: now.AddDate(1, 0, 0).Format(time.RFC3339)})
+
+	t.Setenv("CACHEFLY_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("CACHEFLY_API_BASE_URL", srv.URL)
+	t.Setenv("CACHEFLY_API_TOKEN", srv.Token)
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"certs", "expiring", "--within", "720h"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "soon.example.com") || strings.Contains(stdout.String(), "later.example.com") {
+		t.Errorf("Expected only the certificate expiring soon, got %q", stdout.String())
+	}
+}
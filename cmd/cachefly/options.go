@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+)
+
+func optionsCommand() *command {
+	return &command{
+		name:    "options",
+		summary: "Read and change service options",
+		sub: []*command{
+			{
+				name: "get", args: "<service-id> [option]", summary: "Show all options or a single option",
+				setup: func(fs *flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						if len(args) != 1 && len(args) != 2 {
+							return usageErrorf("expected <service-id> [option]")
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						opts, err := c.ServiceOptions.GetOptions(ctx, args[0])
+						if err != nil {
+							return err
+						}
+						if len(args) == 1 {
+							return printValue(e, opts)
+						}
+						value, ok := opts[args[1]]
+						if !ok {
+							return usageErrorf("option %q is not set on service %s", args[1], args[0])
+						}
+						return printValue(e, value)
+					}
+				},
+			},
+			{
+				name: "set", args: "<service-id> <option>=<value>...", summary: "Update options",
+				setup: func(fs *flag.FlagSet) runFunc {
+					dryRun := fs.Bool("dry-run", false, "show the changes without applying them")
+					return func(ctx context.Context, e *env, args []string) error {
+						if len(args) < 2 {
+							return usageErrorf("expected <service-id> <option>=<value>...")
+						}
+						update := api.ServiceOptions{}
+						for _, arg := range args[1:] {
+							name, value, ok := strings.Cut(arg, "=")
+							if !ok || name == "" {
+								return usageErrorf("invalid option %q, expected <option>=<value>", arg)
+							}
+							update[name] = parseOptionValue(value)
+						}
+
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						if *dryRun {
+							plan, err := c.ServiceOptions.PlanOptions(ctx, args[0], update)
+							if err != nil {
+								return err
+							}
+							if e.output != "table" {
+								return printValue(e, plan.Changes)
+							}
+							fmt.Fprint(e.stdout, plan)
+							return nil
+						}
+						updated, err := c.ServiceOptions.UpdateOptions(ctx, args[0], update)
+						if err != nil {
+							return err
+						}
+						return printValue(e, updated)
+					}
+				},
+			},
+			{
+				name: "metadata", args: "<service-id>", summary: "Show the options metadata or save a snapshot of it",
+				setup: func(fs *flag.FlagSet) runFunc {
+					save := fs.String("save", "", "write the metadata to this file for offline validation")
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 1, "<service-id>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						metadata, err := c.ServiceOptions.GetOptionsMetadata(ctx, args[0])
+						if err != nil {
+							return err
+						}
+						if *save == "" {
+							return printItems(e, metadata.Data, optionMetadataColumns)
+						}
+						if err := api.SaveOptionsMetadata(*save, metadata); err != nil {
+							return err
+						}
+						fmt.Fprintf(e.stderr, "Saved metadata for %d options to %s\n", len(metadata.Data), *save)
+						return nil
+					}
+				},
+			},
+			{
+				name: "validate", args: "--metadata <file> <options-file>...", summary: "Validate options documents offline",
+				setup: func(fs *flag.FlagSet) runFunc {
+					metadataFile := fs.String("metadata", "", "metadata snapshot saved with \"options metadata --save\"")
+					return func(ctx context.Context, e *env, args []string) error {
+						if *metadataFile == "" || len(args) == 0 {
+							return usageErrorf("expected --metadata <file> <options-file>...")
+						}
+						metadata, err := api.LoadOptionsMetadata(*metadataFile)
+						if err != nil {
+							return err
+						}
+						var failed error
+						for _, file := range args {
+							data, err := os.ReadFile(file)
+							if err != nil {
+								return err
+							}
+							var options api.ServiceOptions
+							if err := json.Unmarshal(data, &options); err != nil {
+								return fmt.Errorf("failed to decode %s: %w", file, err)
+							}
+							if err := api.ValidateOptions(options, metadata); err != nil {
+								var verr api.ServiceOptionsValidationError
+								if !errors.As(err, &verr) {
+									return err
+								}
+								for _, v := range verr.Errors {
+									fmt.Fprintf(e.stderr, "%s: %s: %s\n", file, v.Field, v.Message)
+								}
+								failed = err
+								continue
+							}
+							fmt.Fprintf(e.stdout, "%s: OK\n", file)
+						}
+						return failed
+					}
+				},
+			},
+		},
+	}
+}
+
+var optionMetadataColumns = []column[api.OptionMetadata]{
+	{"KEY", func(o api.OptionMetadata) string { return api.OptionKey(o) }},
+	{"NAME", func(o api.OptionMetadata) string { return o.Name }},
+	{"TYPE", func(o api.OptionMetadata) string { return o.Type }},
+	{"GROUP", func(o api.OptionMetadata) string { return o.Group }},
+	{"READ ONLY", func(o api.OptionMetadata) string { return yesNo(o.ReadOnly) }},
+}
+
+// parseOptionValue reads a value as JSON so that true, 3600 or
+// {"enabled":true,"value":60} keep their type; anything else is a string.
+func parseOptionValue(s string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err == nil {
+		return v
+	}
+	return s
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// column describes one column of table output.
+type column[T any] struct {
+	header string
+	value  func(T) string
+}
+
+// printItems writes a list in the selected output format.
+func printItems[T any](e *env, items []T, cols []column[T]) error {
+	if items == nil {
+		items = []T{}
+	}
+	switch e.output {
+	case "json":
+		return writeJSON(e.stdout, items)
+	case "yaml":
+		return writeYAML(e.stdout, items)
+	}
+
+	tw := tabwriter.NewWriter(e.stdout, 0, 0, 3, ' ', 0)
+	headers := make([]string, len(cols))
+	for i, c := range cols {
+		headers[i] = c.header
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, item := range items {
+		values := make([]string, len(cols))
+		for i, c := range cols {
+			values[i] = c.value(item)
+		}
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	return tw.Flush()
+}
+
+// printItem writes a single object; in table format it is shown as one row.
+func printItem[T any](e *env, item T, cols []column[T]) error {
+	switch e.output {
+	case "json":
+		return writeJSON(e.stdout, item)
+	case "yaml":
+		return writeYAML(e.stdout, item)
+	}
+	return printItems(e, []T{item}, cols)
+}
+
+// printValue writes an arbitrary value; table format falls back to YAML,
+// which reads well for nested documents.
+func printValue(e *env, v interface{}) error {
+	if e.output == "json" {
+		return writeJSON(e.stdout, v)
+	}
+	return writeYAML(e.stdout, v)
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeYAML writes v as a YAML document. v is first converted to its JSON
+// form so struct tags and omitempty apply exactly as in JSON output.
+func writeYAML(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return err
+	}
+
+	for _, line := range yamlLines(generic) {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func yamlLines(v interface{}) []string {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if len(t) == 0 {
+			return []string{"{}"}
+		}
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var lines []string
+		for _, k := range keys {
+			child := yamlLines(t[k])
+			if isYAMLCollection(t[k]) {
+				lines = append(lines, yamlScalar(k)+":")
+				for _, l := range child {
+					lines = append(lines, "  "+l)
+				}
+			} else {
+				lines = append(lines, yamlScalar(k)+": "+child[0])
+			}
+		}
+		return lines
+
+	case []interface{}:
+		if len(t) == 0 {
+			return []string{"[]"}
+		}
+		var lines []string
+		for _, item := range t {
+			child := yamlLines(item)
+			lines = append(lines, "- "+child[0])
+			for _, l := range child[1:] {
+				lines = append(lines, "  "+l)
+			}
+		}
+		return lines
+
+	default:
+		return []string{yamlScalar(v)}
+	}
+}
+
+// isYAMLCollection reports whether v is written as a block on its own lines.
+func isYAMLCollection(v interface{}) bool {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return len(t) > 0
+	case []interface{}:
+		return len(t) > 0
+	}
+	return false
+}
+
+func yamlScalar(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(t)
+	case json.Number:
+		return t.String()
+	case string:
+		if yamlNeedsQuotes(t) {
+			return strconv.Quote(t)
+		}
+		return t
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// yamlNeedsQuotes reports whether a plain scalar would be read back as
+// something other than the same string.
+func yamlNeedsQuotes(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return true
+	}
+	if strings.ContainsAny(s, ":#{}[],&*!|>'\"%@`\n\r\t\\") {
+		return true
+	}
+	switch s[0] {
+	case '-', '?', '~':
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "y", "n", "null":
+		return true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	return false
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func deref[T any](p *T) string {
+	if p == nil {
+		return ""
+	}
+	return fmt.Sprint(*p)
+}
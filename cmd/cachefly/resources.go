@@ -0,0 +1,572 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+)
+
+var originColumns = []column[api.Origin]{
+	{"ID", func(o api.Origin) string { return o.ID }},
+	{"NAME", func(o api.Origin) string { return deref(o.Name) }},
+	{"TYPE", func(o api.Origin) string { return o.Type }},
+	{"HOSTNAME", func(o api.Origin) string { return deref(o.Hostname) }},
+	{"SCHEME", func(o api.Origin) string { return deref(o.Scheme) }},
+}
+
+func originsCommand() *command {
+	return &command{
+		name:    "origins",
+		summary: "List, inspect and delete origins",
+		sub: []*command{
+			{
+				name: "list", summary: "List origins",
+				setup: func(fs *flag.FlagSet) runFunc {
+					typ := fs.String("type", "", "filter by origin type")
+					limit := fs.Int("limit", 0, "maximum number of origins (0 for all)")
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						origins, err := collect(c.Origins.All(ctx, api.ListOriginsOptions{Type: *typ}), *limit)
+						if err != nil {
+							return err
+						}
+						return printItems(e, origins, originColumns)
+					}
+				},
+			},
+			{
+				name: "get", args: "<origin-id>", summary: "Show an origin",
+				setup: func(fs *flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 1, "<origin-id>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						o, err := c.Origins.GetByID(ctx, args[0], "")
+						if err != nil {
+							return err
+						}
+						return printItem(e, *o, originColumns)
+					}
+				},
+			},
+			{
+				name: "delete", args: "<origin-id>", summary: "Delete an origin",
+				setup: func(fs *flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 1, "<origin-id>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						return c.Origins.Delete(ctx, args[0])
+					}
+				},
+			},
+		},
+	}
+}
+
+var certificateColumns = []column[api.Certificate]{
+	{"ID", func(c api.Certificate) string { return c.ID }},
+	{"COMMON NAME", func(c api.Certificate) string { return c.SubjectCommonName }},
+	{"NOT AFTER", func(c api.Certificate) string { return c.NotAfter }},
+	{"EXPIRING", func(c api.Certificate) string { return yesNo(c.Expiring || c.Expired) }},
+	{"IN USE", func(c api.Certificate) string { return yesNo(c.InUse) }},
+}
+
+var certificateExpiryColumns = []column[api.CertificateExpiry]{
+	{"ID", func(c api.CertificateExpiry) string { return c.ID }},
+	{"COMMON NAME", func(c api.CertificateExpiry) string { return c.SubjectCommonName }},
+	{"NOT AFTER", func(c api.CertificateExpiry) string { return c.NotAfter }},
+	{"DAYS LEFT", func(c api.CertificateExpiry) string {
+		if c.ExpiresAt().IsZero() {
+			return "-"
+		}
+		return fmt.Sprint(int(c.ExpiresIn.Hours() / 24))
+	}},
+	{"SERVICES", func(c api.CertificateExpiry) string { return strings.Join(c.Services, ",") }},
+	{"DOMAINS", func(c api.CertificateExpiry) string { return strings.Join(c.Domains, ",") }},
+}
+
+func certsCommand() *command {
+	return &command{
+		name:    "certs",
+		summary: "Manage TLS certificates",
+		sub: []*command{
+			{
+				name: "list", summary: "List certificates",
+				setup: func(fs *flag.FlagSet) runFunc {
+					search := fs.String("search", "", "filter by name")
+					limit := fs.Int("limit", 0, "maximum number of certificates (0 for all)")
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						certs, err := collect(c.Certificates.All(ctx, api.ListCertificatesOptions{Search: *search}), *limit)
+						if err != nil {
+							return err
+						}
+						return printItems(e, certs, certificateColumns)
+					}
+				},
+			},
+			{
+				name: "get", args: "<certificate-id>", summary: "Show a certificate",
+				setup: func(fs *flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 1, "<certificate-id>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						cert, err := c.Certificates.GetByID(ctx, args[0], "")
+						if err != nil {
+							return err
+						}
+						return printItem(e, *cert, certificateColumns)
+					}
+				},
+			},
+			{
+				name: "create", args: "--cert <file> --key <file>", summary: "Upload a certificate",
+				setup: func(fs *flag.FlagSet) runFunc {
+					certFile := fs.String("cert", "", "PEM certificate (chain) file")
+					keyFile := fs.String("key", "", "PEM private key file")
+					var chainFiles, domains stringsFlag
+					fs.Var(&chainFiles, "chain", "PEM intermediate certificates file (repeatable)")
+					fs.Var(&domains, "domain", "domain the certificate must cover (repeatable)")
+					minValidity := fs.Duration("min-validity", 0, "reject certificates expiring sooner than this")
+					password := fs.String("password", "", "private key password; skips the local checks")
+					return func(ctx context.Context, e *env, args []string) error {
+						if *certFile == "" || *keyFile == "" {
+							return usageErrorf("--cert and --key are required")
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+
+						var cert *api.Certificate
+						if *password != "" {
+							// encrypted keys cannot be checked locally
+							certPEM, err := os.ReadFile(*certFile)
+							if err != nil {
+								return err
+							}
+							keyPEM, err := os.ReadFile(*keyFile)
+							if err != nil {
+								return err
+							}
+							cert, err = c.Certificates.Create(ctx, api.CreateCertificateRequest{Certificate: string(certPEM), CertificateKey: string(keyPEM), Password: *password})
+							if err != nil {
+								return err
+							}
+						} else {
+							bundle, err := api.LoadCertificateBundle(*certFile, *keyFile, chainFiles...)
+							if err != nil {
+								return err
+							}
+							cert, err = c.Certificates.Upload(ctx, bundle, api.CertificateCheckOptions{Domains: domains, MinValidity: *minValidity})
+							if err != nil {
+								return err
+							}
+						}
+						return printItem(e, *cert, certificateColumns)
+					}
+				},
+			},
+			{
+				name: "expiring", summary: "List certificates in use that expire soon",
+				setup: func(fs *flag.FlagSet) runFunc {
+					within := fs.Duration("within", 30*24*time.Hour, "report certificates expiring within this window")
+					all := fs.Bool("all", false, "include certificates not in use")
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						certs, err := c.Certificates.Expiring(ctx, api.ExpiringCertificatesOptions{Within: *within, IncludeUnused: *all})
+						if err != nil {
+							return err
+						}
+						return printItems(e, certs, certificateExpiryColumns)
+					}
+				},
+			},
+			{
+				name: "rotate", args: "<certificate-id> --cert <file> --key <file>", summary: "Upload a replacement certificate and delete the old one if unused",
+				setup: func(fs *flag.FlagSet) runFunc {
+					certFile := fs.String("cert", "", "PEM certificate (chain) file of the replacement")
+					keyFile := fs.String("key", "", "PEM private key file of the replacement")
+					var chainFiles stringsFlag
+					fs.Var(&chainFiles, "chain", "PEM intermediate certificates file (repeatable)")
+					minValidity := fs.Duration("min-validity", 0, "reject replacements expiring sooner than this")
+					keepOld := fs.Bool("keep-old", false, "do not delete the old certificate")
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 1, "<certificate-id>"); err != nil {
+							return err
+						}
+						if *certFile == "" || *keyFile == "" {
+							return usageErrorf("--cert and --key are required")
+						}
+						bundle, err := api.LoadCertificateBundle(*certFile, *keyFile, chainFiles...)
+						if err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						rotation, err := c.Certificates.Rotate(ctx, args[0], bundle, api.RotateCertificateOptions{
+							Check:   api.CertificateCheckOptions{MinValidity: *minValidity},
+							KeepOld: *keepOld,
+						})
+						if rotation != nil && rotation.New != nil {
+							printItem(e, *rotation.New, certificateColumns)
+							switch {
+							case rotation.Deleted:
+								fmt.Fprintf(e.stderr, "Deleted certificate %s\n", args[0])
+							case err == nil && rotation.Old.InUse:
+								fmt.Fprintf(e.stderr, "Certificate %s is still in use; move its domains to %s, then delete it\n", args[0], rotation.New.ID)
+							}
+						}
+						return err
+					}
+				},
+			},
+			{
+				name: "delete", args: "<certificate-id>", summary: "Delete a certificate",
+				setup: func(fs *flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 1, "<certificate-id>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						return c.Certificates.Delete(ctx, args[0])
+					}
+				},
+			},
+		},
+	}
+}
+
+var warmingColumns = []column[api.CacheWarmingTask]{
+	{"ID", func(t api.CacheWarmingTask) string { return t.ID }},
+	{"NAME", func(t api.CacheWarmingTask) string { return t.Name }},
+	{"STATUS", func(t api.CacheWarmingTask) string { return t.Status }},
+	{"TARGETS", func(t api.CacheWarmingTask) string { return fmt.Sprint(len(t.Targets)) }},
+	{"CREATED", func(t api.CacheWarmingTask) string { return t.CreatedAt }},
+}
+
+func warmCommand() *command {
+	return &command{
+		name:    "warm",
+		summary: "Manage cache warming tasks",
+		sub: []*command{
+			{
+				name: "list", summary: "List cache warming tasks",
+				setup: func(fs *flag.FlagSet) runFunc {
+					limit := fs.Int("limit", 0, "maximum number of tasks (0 for all)")
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						tasks, err := collect(c.CacheWarming.All(ctx, api.ListCacheWarmingTasksOptions{}), *limit)
+						if err != nil {
+							return err
+						}
+						return printItems(e, tasks, warmingColumns)
+					}
+				},
+			},
+			{
+				name: "get", args: "<task-id>", summary: "Show a cache warming task",
+				setup: func(fs *flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 1, "<task-id>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						task, err := c.CacheWarming.GetByID(ctx, args[0])
+						if err != nil {
+							return err
+						}
+						return printItem(e, *task, warmingColumns)
+					}
+				},
+			},
+			{
+				name: "create", args: "[--sitemap <file>] [<url>...]", summary: "Warm the cache for a list of URLs",
+				setup: func(fs *flag.FlagSet) runFunc {
+					name := fs.String("name", "", "task name")
+					var regions stringsFlag
+					fs.Var(&regions, "region", "delivery region to warm (repeatable, default all)")
+					sitemap := fs.String("sitemap", "", "warm the pages of this sitemap file")
+					perTask := fs.Int("per-task", api.DefaultWarmingTargetsPerTask, "maximum number of URLs per task")
+					perRegion := fs.Bool("per-region", false, "create separate tasks for each region")
+					wait := fs.Bool("wait", false, "wait until the tasks are done")
+					interval := fs.Duration("interval", api.DefaultCacheWarmingPollInterval, "poll interval with --wait")
+					return func(ctx context.Context, e *env, args []string) error {
+						targets, err := api.WarmingTargets(args)
+						if err != nil {
+							return usageErrorf("%v", err)
+						}
+						if *sitemap != "" {
+							pages, err := api.SitemapFileTargets(*sitemap)
+							if err != nil {
+								return err
+							}
+							if targets, err = api.WarmingTargets(append(targets, pages...)); err != nil {
+								return err
+							}
+						}
+						if len(targets) == 0 {
+							return usageErrorf("at least one URL or --sitemap is required")
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						tasks, err := c.CacheWarming.CreateTasks(ctx, targets, api.CacheWarmingTaskOptions{
+							Name:           *name,
+							Regions:        regions,
+							PerRegion:      *perRegion,
+							TargetsPerTask: *perTask,
+						})
+						if err != nil {
+							if len(tasks) > 0 {
+								printItems(e, tasks, warmingColumns)
+							}
+							return err
+						}
+						if *wait {
+							for _, task := range tasks {
+								if err := waitWarming(ctx, e, c, task.ID, *interval); err != nil {
+									return err
+								}
+							}
+							return nil
+						}
+						return printItems(e, tasks, warmingColumns)
+					}
+				},
+			},
+			{
+				name: "wait", args: "<task-id>", summary: "Wait for a cache warming task to finish",
+				setup: func(fs *flag.FlagSet) runFunc {
+					interval := fs.Duration("interval", api.DefaultCacheWarmingPollInterval, "poll interval")
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 1, "<task-id>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						return waitWarming(ctx, e, c, args[0], *interval)
+					}
+				},
+			},
+			{
+				name: "delete", args: "<task-id>", summary: "Delete a cache warming task",
+				setup: func(fs *flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 1, "<task-id>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						return c.CacheWarming.DeleteByID(ctx, args[0])
+					}
+				},
+			},
+		},
+	}
+}
+
+// waitWarming reports the status changes of a task on stderr until it is done
+// and prints its final state.
+func waitWarming(ctx context.Context, e *env, c *cachefly.Client, id string, interval time.Duration) error {
+	var last api.CacheWarmingTask
+	for task, err := range c.CacheWarming.Watch(ctx, id, interval) {
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stderr, "Task %s: %s\n", id, task.Status)
+		last = task
+	}
+	return printItem(e, last, warmingColumns)
+}
+
+var userColumns = []column[api.User]{
+	{"ID", func(u api.User) string { return u.ID }},
+	{"USERNAME", func(u api.User) string { return u.Username }},
+	{"EMAIL", func(u api.User) string { return u.Email }},
+	{"FULL NAME", func(u api.User) string { return u.FullName }},
+	{"STATUS", func(u api.User) string { return u.Status }},
+}
+
+func usersCommand() *command {
+	return &command{
+		name:    "users",
+		summary: "List and inspect users",
+		sub: []*command{
+			{
+				name: "list", summary: "List users",
+				setup: func(fs *flag.FlagSet) runFunc {
+					search := fs.String("search", "", "filter by name or email")
+					limit := fs.Int("limit", 0, "maximum number of users (0 for all)")
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						users, err := collect(c.Users.All(ctx, api.ListUsersOptions{Search: *search}), *limit)
+						if err != nil {
+							return err
+						}
+						return printItems(e, users, userColumns)
+					}
+				},
+			},
+			{
+				name: "get", args: "<user-id>", summary: "Show a user",
+				setup: func(fs *flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 1, "<user-id>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						u, err := c.Users.GetByID(ctx, args[0], "")
+						if err != nil {
+							return err
+						}
+						return printItem(e, *u, userColumns)
+					}
+				},
+			},
+			{
+				name: "me", summary: "Show the authenticated user",
+				setup: func(fs *flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						u, err := c.Users.GetCurrentUser(ctx)
+						if err != nil {
+							return err
+						}
+						return printItem(e, *u, userColumns)
+					}
+				},
+			},
+		},
+	}
+}
+
+var logTargetColumns = []column[api.LogTarget]{
+	{"ID", func(t api.LogTarget) string { return t.ID }},
+	{"NAME", func(t api.LogTarget) string { return deref(t.Name) }},
+	{"TYPE", func(t api.LogTarget) string { return t.Type }},
+	{"ACCESS LOGS", func(t api.LogTarget) string { return joinServices(t.AccessLogsServices) }},
+	{"ORIGIN LOGS", func(t api.LogTarget) string { return joinServices(t.OriginLogsServices) }},
+}
+
+func joinServices(s *[]string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.Join(*s, ",")
+}
+
+func logTargetsCommand() *command {
+	return &command{
+		name:    "logtargets",
+		summary: "List, inspect and delete log targets",
+		sub: []*command{
+			{
+				name: "list", summary: "List log targets",
+				setup: func(fs *flag.FlagSet) runFunc {
+					typ := fs.String("type", "", "filter by log target type")
+					limit := fs.Int("limit", 0, "maximum number of log targets (0 for all)")
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						targets, err := collect(c.LogTargets.All(ctx, api.ListLogTargetsOptions{Type: *typ}), *limit)
+						if err != nil {
+							return err
+						}
+						return printItems(e, targets, logTargetColumns)
+					}
+				},
+			},
+			{
+				name: "get", args: "<log-target-id>", summary: "Show a log target",
+				setup: func(fs *flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 1, "<log-target-id>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						t, err := c.LogTargets.GetByID(ctx, args[0])
+						if err != nil {
+							return err
+						}
+						return printItem(e, *t, logTargetColumns)
+					}
+				},
+			},
+			{
+				name: "delete", args: "<log-target-id>", summary: "Delete a log target",
+				setup: func(fs *flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 1, "<log-target-id>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						return c.LogTargets.DeleteByID(ctx, args[0])
+					}
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"iter"
+	"net"
+	"strings"
+
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+)
+
+// collect reads up to limit items from an All iterator, or every item when
+// limit is 0.
+func collect[T any](seq iter.Seq2[T, error], limit int) ([]T, error) {
+	if limit > 0 {
+		return api.ListN(seq, limit)
+	}
+	return api.ListAll(seq)
+}
+
+// stringsFlag is a repeatable flag that also accepts comma separated values.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string { return strings.Join(*f, ",") }
+
+func (f *stringsFlag) Set(v string) error {
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			*f = append(*f, s)
+		}
+	}
+	return nil
+}
+
+var serviceColumns = []column[api.Service]{
+	{"ID", func(s api.Service) string { return s.ID }},
+	{"NAME", func(s api.Service) string { return s.Name }},
+	{"UNIQUE NAME", func(s api.Service) string { return s.UniqueName }},
+	{"STATUS", func(s api.Service) string { return s.Status }},
+	{"AUTO SSL", func(s api.Service) string { return yesNo(s.AutoSSL) }},
+}
+
+func servicesCommand() *command {
+	return &command{
+		name:    "services",
+		summary: "List, inspect, create and purge services",
+		sub: []*command{
+			{
+				name: "list", summary: "List services",
+				setup: func(fs *flag.FlagSet) runFunc {
+					status := fs.String("status", "", "filter by status (ACTIVE, DEACTIVATED)")
+					limit := fs.Int("limit", 0, "maximum number of services (0 for all)")
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						services, err := collect(c.Services.All(ctx, api.ListOptions{Status: *status}), *limit)
+						if err != nil {
+							return err
+						}
+						return printItems(e, services, serviceColumns)
+					}
+				},
+			},
+			{
+				name: "get", args: "<service-id>", summary: "Show a service",
+				setup: func(fs *flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 1, "<service-id>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						svc, err := c.Services.GetByID(ctx, args[0])
+						if err != nil {
+							return err
+						}
+						return printItem(e, *svc, serviceColumns)
+					}
+				},
+			},
+			{
+				name: "create", args: "--name <name> --unique-name <unique-name>", summary: "Create a service",
+				setup: func(fs *flag.FlagSet) runFunc {
+					name := fs.String("name", "", "service name")
+					uniqueName := fs.String("unique-name", "", "unique service name")
+					description := fs.String("description", "", "service description")
+					return func(ctx context.Context, e *env, args []string) error {
+						if *name == "" || *uniqueName == "" {
+							return usageErrorf("--name and --unique-name are required")
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						svc, err := c.Services.Create(ctx, api.CreateServiceRequest{Name: *name, UniqueName: *uniqueName, Description: *description})
+						if err != nil {
+							return err
+						}
+						return printItem(e, *svc, serviceColumns)
+					}
+				},
+			},
+			{
+				name: "purge", args: "<service-id> (--all | <url-or-path>...)", summary: "Purge cached content",
+				setup: func(fs *flag.FlagSet) runFunc {
+					all := fs.Bool("all", false, "purge everything")
+					batchSize := fs.Int("batch-size", api.DefaultPurgeBatchSize, "paths per purge request")
+					concurrency := fs.Int("concurrency", api.DefaultPurgeConcurrency, "purge requests in flight")
+					return func(ctx context.Context, e *env, args []string) error {
+						if len(args) == 0 {
+							return usageErrorf("expected <service-id>")
+						}
+						if *all == (len(args) > 1) {
+							return usageErrorf("use either --all or one or more paths")
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						if *all {
+							if err := c.Services.Purge(ctx, args[0], api.PurgeRequest{All: true}); err != nil {
+								return err
+							}
+							fmt.Fprintln(e.stderr, "Purge requested")
+							return nil
+						}
+
+						report, err := c.Services.PurgeURLs(ctx, args[0], args[1:], api.PurgeOptions{BatchSize: *batchSize, Concurrency: *concurrency})
+						if err != nil {
+							return err
+						}
+						for _, res := range report.Failed() {
+							fmt.Fprintf(e.stderr, "%s: %v\n", res.Input, res.Err)
+						}
+						fmt.Fprintf(e.stderr, "Purged %d of %d paths in %d requests\n", report.Purged(), len(report.Results), report.Batches)
+						return report.Err()
+					}
+				},
+			},
+			{
+				name: "activate", args: "<service-id>", summary: "Activate a service",
+				setup: func(fs *flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 1, "<service-id>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						svc, err := c.Services.ActivateServiceByID(ctx, args[0])
+						if err != nil {
+							return err
+						}
+						return printItem(e, *svc, serviceColumns)
+					}
+				},
+			},
+			{
+				name: "deactivate", args: "<service-id>", summary: "Deactivate a service",
+				setup: func(fs *flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 1, "<service-id>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						svc, err := c.Services.DeactivateServiceByID(ctx, args[0])
+						if err != nil {
+							return err
+						}
+						return printItem(e, *svc, serviceColumns)
+					}
+				},
+			},
+		},
+	}
+}
+
+var domainColumns = []column[api.ServiceDomain]{
+	{"ID", func(d api.ServiceDomain) string { return d.ID }},
+	{"NAME", func(d api.ServiceDomain) string { return d.Name }},
+	{"VALIDATION MODE", func(d api.ServiceDomain) string { return d.ValidationMode }},
+	{"VALIDATION STATUS", func(d api.ServiceDomain) string { return string(d.ValidationStatus) }},
+}
+
+func domainsCommand() *command {
+	return &command{
+		name:    "domains",
+		summary: "Manage the domains of a service",
+		sub: []*command{
+			{
+				name: "list", args: "<service-id>", summary: "List the domains of a service",
+				setup: func(fs *flag.FlagSet) runFunc {
+					search := fs.String("search", "", "filter by name")
+					limit := fs.Int("limit", 0, "maximum number of domains (0 for all)")
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 1, "<service-id>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						domains, err := collect(c.ServiceDomains.All(ctx, args[0], api.ListServiceDomainsOptions{Search: *search}), *limit)
+						if err != nil {
+							return err
+						}
+						return printItems(e, domains, domainColumns)
+					}
+				},
+			},
+			{
+				name: "get", args: "<service-id> <domain-id>", summary: "Show a domain",
+				setup: func(fs *flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 2, "<service-id> <domain-id>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						d, err := c.ServiceDomains.GetByID(ctx, args[0], args[1], "")
+						if err != nil {
+							return err
+						}
+						return printItem(e, *d, domainColumns)
+					}
+				},
+			},
+			{
+				name: "create", args: "<service-id> <name>", summary: "Add a domain to a service",
+				setup: func(fs *flag.FlagSet) runFunc {
+					description := fs.String("description", "", "domain description")
+					mode := fs.String("validation-mode", "", "validation mode")
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 2, "<service-id> <name>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						d, err := c.ServiceDomains.Create(ctx, args[0], api.CreateServiceDomainRequest{Name: args[1], Description: *description, ValidationMode: *mode})
+						if err != nil {
+							return err
+						}
+						return printItem(e, *d, domainColumns)
+					}
+				},
+			},
+			{
+				name: "validate", args: "<service-id> <domain-id>", summary: "Validate a domain and wait for the result",
+				setup: func(fs *flag.FlagSet) runFunc {
+					checkDNS := fs.Bool("check-dns", false, "check the validation records before requesting validation")
+					interval := fs.Duration("interval", api.DefaultDomainValidationPollInterval, "poll interval")
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 2, "<service-id> <domain-id>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						d, err := c.ServiceDomains.GetByID(ctx, args[0], args[1], "")
+						if err != nil {
+							return err
+						}
+						if records, err := d.ValidationRecords(); err == nil && len(records) > 0 {
+							fmt.Fprintln(e.stderr, "Required DNS records:")
+							for _, r := range records {
+								fmt.Fprintln(e.stderr, " ", r)
+							}
+						}
+						if challenge, err := d.HTTPChallenge(); err == nil {
+							fmt.Fprintf(e.stderr, "HTTP validation: %s must be served by CacheFly through %s\n", challenge.URL, challenge.Target)
+						}
+						opts := api.ValidateDomainOptions{PollInterval: *interval}
+						if *checkDNS {
+							opts.Resolver = net.DefaultResolver
+						}
+						d, err = c.ServiceDomains.Validate(ctx, args[0], args[1], opts)
+						if err != nil {
+							return err
+						}
+						return printItem(e, *d, domainColumns)
+					}
+				},
+			},
+			{
+				name: "delete", args: "<service-id> <domain-id>", summary: "Remove a domain from a service",
+				setup: func(fs *flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						if err := requireArgs(args, 2, "<service-id> <domain-id>"); err != nil {
+							return err
+						}
+						c, err := e.cachefly()
+						if err != nil {
+							return err
+						}
+						return c.ServiceDomains.DeleteByID(ctx, args[0], args[1])
+					}
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+)
+
+// statsFetcher queries one stats endpoint at account scope, or service scope
+// when sid is set.
+type statsFetcher func(ctx context.Context, c *cachefly.Client, sid string, opts api.StatsQueryOptions) ([]api.StatsDataPoint, error)
+
+// rawRows returns the untyped rows of a typed stats response.
+func rawRows[T interface{ Raw() api.StatsDataPoint }](res *api.StatsResult[T], err error) ([]api.StatsDataPoint, error) {
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]api.StatsDataPoint, 0, len(res.Data))
+	for _, r := range res.Data {
+		rows = append(rows, r.Raw())
+	}
+	return rows, nil
+}
+
+var statsEndpoints = []struct {
+	name     string
+	summary  string
+	realtime bool
+	fetch    statsFetcher
+}{
+	{"pop", "Traffic per POP", false, func(ctx context.Context, c *cachefly.Client, sid string, o api.StatsQueryOptions) ([]api.StatsDataPoint, error) {
+		if sid != "" {
+			return rawRows(c.ServiceStats.POP(ctx, sid, o))
+		}
+		return rawRows(c.AccountStats.POP(ctx, o))
+	}},
+	{"country", "Traffic per country", false, func(ctx context.Context, c *cachefly.Client, sid string, o api.StatsQueryOptions) ([]api.StatsDataPoint, error) {
+		if sid != "" {
+			return rawRows(c.ServiceStats.Country(ctx, sid, o))
+		}
+		return rawRows(c.AccountStats.Country(ctx, o))
+	}},
+	{"cache", "Cache hits and misses", false, func(ctx context.Context, c *cachefly.Client, sid string, o api.StatsQueryOptions) ([]api.StatsDataPoint, error) {
+		if sid != "" {
+			return rawRows(c.ServiceStats.Cache(ctx, sid, o))
+		}
+		return rawRows(c.AccountStats.Cache(ctx, o))
+	}},
+	{"status", "Requests per status code", false, func(ctx context.Context, c *cachefly.Client, sid string, o api.StatsQueryOptions) ([]api.StatsDataPoint, error) {
+		if sid != "" {
+			return rawRows(c.ServiceStats.Status(ctx, sid, o))
+		}
+		return rawRows(c.AccountStats.Status(ctx, o))
+	}},
+	{"origin", "Origin traffic", false, func(ctx context.Context, c *cachefly.Client, sid string, o api.StatsQueryOptions) ([]api.StatsDataPoint, error) {
+		if sid != "" {
+			return rawRows(c.ServiceStats.Origin(ctx, sid, o))
+		}
+		return rawRows(c.AccountStats.Origin(ctx, o))
+	}},
+	{"storage", "Storage usage (account only)", false, func(ctx context.Context, c *cachefly.Client, sid string, o api.StatsQueryOptions) ([]api.StatsDataPoint, error) {
+		if sid != "" {
+			return nil, usageErrorf("storage stats are only available per account")
+		}
+		return rawRows(c.AccountStats.Storage(ctx, o))
+	}},
+	{"path", "Traffic per path", false, func(ctx context.Context, c *cachefly.Client, sid string, o api.StatsQueryOptions) ([]api.StatsDataPoint, error) {
+		if sid != "" {
+			return rawRows(c.ServiceStats.Path(ctx, sid, o))
+		}
+		return rawRows(c.AccountStats.Path(ctx, o))
+	}},
+	{"referer", "Traffic per referer", false, func(ctx context.Context, c *cachefly.Client, sid string, o api.StatsQueryOptions) ([]api.StatsDataPoint, error) {
+		if sid != "" {
+			return rawRows(c.ServiceStats.Referer(ctx, sid, o))
+		}
+		return rawRows(c.AccountStats.Referer(ctx, o))
+	}},
+	{"realtime", "Realtime traffic", true, func(ctx context.Context, c *cachefly.Client, sid string, o api.StatsQueryOptions) ([]api.StatsDataPoint, error) {
+		if sid != "" {
+			return rawRows(c.ServiceStats.Realtime(ctx, sid, o))
+		}
+		return rawRows(c.AccountStats.Realtime(ctx, o))
+	}},
+}
+
+func statsCommand() *command {
+	cmd := &command{name: "stats", summary: "Query account or service statistics"}
+	for _, ep := range statsEndpoints {
+		args := "[--service <id>] [--from <date>] [--to <date>]"
+		if ep.realtime {
+			args = "[--service <id>]"
+		}
+		cmd.sub = append(cmd.sub, &command{
+			name: ep.name, args: args, summary: ep.summary,
+			setup: func(fs *flag.FlagSet) runFunc {
+				sid := fs.String("service", "", "service ID (account-wide when empty)")
+				from := fs.String("from", "", "start date, YYYY-MM-DD or RFC 3339 (default 7 days ago)")
+				to := fs.String("to", "", "end date, YYYY-MM-DD or RFC 3339 (default today)")
+				limit := fs.Int("limit", 0, "maximum number of rows")
+				var groupBy, sortBy stringsFlag
+				fs.Var(&groupBy, "group-by", "dimensions to group by, e.g. pop,date")
+				fs.Var(&sortBy, "sort-by", "fields to sort by")
+
+				return func(ctx context.Context, e *env, args []string) error {
+					if len(args) != 0 {
+						return usageErrorf("unexpected argument %q", args[0])
+					}
+					opts := api.StatsQueryOptions{Limit: *limit, GroupBy: groupBy, SortBy: sortBy}
+					if !ep.realtime {
+						var err error
+						if opts.From, opts.To, err = statsPeriod(*from, *to, time.Now()); err != nil {
+							return err
+						}
+					}
+
+					c, err := e.cachefly()
+					if err != nil {
+						return err
+					}
+					rows, err := ep.fetch(ctx, c, *sid, opts)
+					if err != nil {
+						return err
+					}
+					return printItems(e, rows, statsColumns(rows, groupBy))
+				}
+			},
+		})
+	}
+	return cmd
+}
+
+// statsPeriod parses the --from and --to flags, defaulting to the last 7 days.
+func statsPeriod(from, to string, now time.Time) (time.Time, time.Time, error) {
+	end := now.UTC().Truncate(24 * time.Hour)
+	if to != "" {
+		t, err := parseDate(to)
+		if err != nil {
+			return time.Time{}, time.Time{}, usageErrorf("invalid --to: %v", err)
+		}
+		end = t
+	}
+	start := end.AddDate(0, 0, -7)
+	if from != "" {
+		t, err := parseDate(from)
+		if err != nil {
+			return time.Time{}, time.Time{}, usageErrorf("invalid --from: %v", err)
+		}
+		start = t
+	}
+	return start, end, nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// statsColumns lists the groupBy dimensions first, then every other field
+// found in the rows in alphabetical order.
+func statsColumns(rows []api.StatsDataPoint, groupBy []string) []column[api.StatsDataPoint] {
+	seen := map[string]bool{}
+	var names []string
+	for _, g := range groupBy {
+		if !seen[g] {
+			seen[g] = true
+			names = append(names, g)
+		}
+	}
+	var rest []string
+	for _, r := range rows {
+		for k := range r {
+			if !seen[k] {
+				seen[k] = true
+				rest = append(rest, k)
+			}
+		}
+	}
+	sort.Strings(rest)
+	names = append(names, rest...)
+
+	cols := make([]column[api.StatsDataPoint], 0, len(names))
+	for _, name := range names {
+		cols = append(cols, column[api.StatsDataPoint]{
+			header: strings.ToUpper(name),
+			value:  func(r api.StatsDataPoint) string { return r.Dimension(name) },
+		})
+	}
+	return cols
+}
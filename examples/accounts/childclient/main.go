@@ -0,0 +1,57 @@
+// Example demonstrates managing child accounts with child-scoped clients.
+//
+// This example shows:
+// - Getting a client authenticated as a child account
+// - Running an operation for every child account of the parent
+//
+// Usage:
+//
+//	export CACHEFLY_API_TOKEN="your-parent-account-token"
+//	go run main.go
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	// Load environment variables from .env file
+	if err := godotenv.Load(); err != nil {
+		log.Printf("⚠️ Warning: unable to load .env file: %v", err)
+	}
+
+	// Read API token
+	token := os.Getenv("CACHEFLY_API_TOKEN")
+	if token == "" {
+		log.Fatal("❌ CACHEFLY_API_TOKEN environment variable is required")
+	}
+
+	// Initialize CacheFly client for the parent account
+	client := cachefly.NewClient(
+		cachefly.WithToken(token),
+	)
+
+	// Count the services of every child account; child tokens are obtained
+	// and refreshed automatically
+	err := client.ForEachChildAccount(context.Background(), func(ctx context.Context, account api.Account, child *cachefly.Client) error {
+		services, err := child.Services.List(ctx, api.ListOptions{Limit: 1})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("🏢 %s (%s): %d services\n", account.CompanyName, account.ID, services.Meta.Count)
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("❌ Failed to list child account services: %v", err)
+	}
+
+	fmt.Println("\n✅ Done")
+}
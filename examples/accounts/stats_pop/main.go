@@ -2,10 +2,10 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
 	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
@@ -23,8 +23,8 @@ func main() {
 	client := cachefly.NewClient(cachefly.WithToken(token))
 
 	opts := api.StatsQueryOptions{
-		From:    "2024-09-01",
-		To:      "2025-09-02",
+		From:    time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+		To:      time.Date(2025, 9, 2, 0, 0, 0, 0, time.UTC),
 		Limit:   10,
 		GroupBy: []string{"pop", "date"},
 	}
@@ -33,9 +33,11 @@ func main() {
 		log.Fatalf("failed to fetch account POP stats: %v", err)
 	}
 
-	out, err := json.MarshalIndent(resp, "", "  ")
-	if err != nil {
-		log.Fatalf("failed to format JSON: %v", err)
+	// one series of daily bytes per POP
+	for _, series := range api.Pivot(resp.Data, func(r api.POPStatsRow) float64 { return r.Bytes }, "pop") {
+		fmt.Println(series.Labels["pop"])
+		for _, p := range series.Points {
+			fmt.Printf("  %s  %.0f bytes\n", p.Time.Format(time.DateOnly), p.Value)
+		}
 	}
-	fmt.Println(string(out))
 }
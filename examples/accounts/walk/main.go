@@ -0,0 +1,68 @@
+// Example demonstrates running a query for every account in the hierarchy.
+//
+// This example shows:
+// - Building the parent/child account tree
+// - Counting the services of each child account, four accounts at a time
+// - Reporting per-account failures without stopping the others
+//
+// Usage:
+//
+//	export CACHEFLY_API_TOKEN="your-parent-account-token"
+//	go run main.go
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	// Load environment variables from .env file
+	if err := godotenv.Load(); err != nil {
+		log.Printf("⚠️ Warning: unable to load .env file: %v", err)
+	}
+
+	// Read API token
+	token := os.Getenv("CACHEFLY_API_TOKEN")
+	if token == "" {
+		log.Fatal("❌ CACHEFLY_API_TOKEN environment variable is required")
+	}
+
+	// Initialize CacheFly client for the parent account
+	client := cachefly.NewClient(
+		cachefly.WithToken(token),
+	)
+
+	// Count the services of every account in the hierarchy
+	results, err := cachefly.WalkAccounts(context.Background(), client, cachefly.WalkOptions{Concurrency: 4, IncludeParent: true},
+		func(ctx context.Context, account api.Account, c *cachefly.Client) (int, error) {
+			resp, err := c.Services.List(ctx, api.ListOptions{Limit: 1})
+			if err != nil {
+				return 0, err
+			}
+			return resp.Meta.Count, nil
+		})
+	if err != nil {
+		log.Fatalf("❌ Failed to list accounts: %v", err)
+	}
+
+	failed := 0
+	for _, r := range results {
+		indent := strings.Repeat("  ", r.Depth)
+		if r.Err != nil {
+			failed++
+			fmt.Printf("%s❌ %s (%s): %v\n", indent, r.Account.CompanyName, r.Account.ID, r.Err)
+			continue
+		}
+		fmt.Printf("%s🏢 %s (%s): %d services\n", indent, r.Account.CompanyName, r.Account.ID, r.Value)
+	}
+	fmt.Printf("\n✅ %d accounts processed, %d failed\n", len(results), failed)
+}
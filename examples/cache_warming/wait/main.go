@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	_ = godotenv.Load()
+	token := os.Getenv("CACHEFLY_API_TOKEN")
+	if token == "" {
+		log.Fatal("CACHEFLY_API_TOKEN is required")
+	}
+	if len(os.Args) < 2 {
+		log.Fatalf("usage: go run main.go <task_id>")
+	}
+
+	id := os.Args[1]
+	client := cachefly.NewClient(cachefly.WithToken(token))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	for task, err := range client.CacheWarming.Watch(ctx, id, 10*time.Second) {
+		var warmErr *api.CacheWarmingError
+		switch {
+		case errors.As(err, &warmErr):
+			log.Fatalf("task stopped at %v: %v", warmErr.Task.Stopped(), err)
+		case err != nil:
+			log.Fatalf("failed to watch task: %v", err)
+		}
+		fmt.Printf("%s %s\n", time.Now().Format(time.TimeOnly), task.Status)
+		if task.State() == api.CacheWarmingCompleted {
+			fmt.Printf("done in %v\n", task.Stopped().Sub(task.Started()))
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Example demonstrates finding certificates that expire soon.
+//
+// This example shows:
+// - Scanning all certificates for ones expiring within a window
+// - Reporting the services and domains each certificate is used by
+//
+// Usage:
+//
+//	export CACHEFLY_API_TOKEN="your-token"
+//	go run main.go
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	// Load environment variables from .env file
+	if err := godotenv.Load(); err != nil {
+		log.Printf("⚠️ Warning: unable to load .env file: %v", err)
+	}
+
+	// Read API token
+	token := os.Getenv("CACHEFLY_API_TOKEN")
+	if token == "" {
+		log.Fatal("❌ CACHEFLY_API_TOKEN environment variable is required")
+	}
+
+	// Initialize CacheFly client
+	client := cachefly.NewClient(
+		cachefly.WithToken(token),
+	)
+
+	// Find certificates in use that expire within 30 days
+	certs, err := client.Certificates.Expiring(context.Background(), api.ExpiringCertificatesOptions{
+		Within: 30 * 24 * time.Hour,
+	})
+	if err != nil {
+		log.Fatalf("❌ Failed to scan certificates: %v", err)
+	}
+	if len(certs) == 0 {
+		fmt.Println("✅ No certificates expire within 30 days")
+		return
+	}
+
+	for _, c := range certs {
+		fmt.Printf("⏰ %s (%s) expires %s, in %v\n", c.SubjectCommonName, c.ID, c.NotAfter, c.ExpiresIn.Round(time.Hour))
+		fmt.Printf("   services: %s\n", strings.Join(c.Services, ", "))
+		fmt.Printf("   domains:  %s\n", strings.Join(c.Domains, ", "))
+	}
+}
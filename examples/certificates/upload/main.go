@@ -0,0 +1,75 @@
+// Example demonstrates uploading a certificate from PEM files after checking
+// it locally.
+//
+// This example shows:
+// - Loading a certificate, its chain and private key from files
+// - Checking the key, chain order, domains and expiry before upload
+// - Reporting each problem found by the checks
+//
+// Usage:
+//
+//	export CACHEFLY_API_TOKEN="your-token"
+//	export CERT_FILE="cert.pem"
+//	export KEY_FILE="key.pem"
+//	export CHAIN_FILE="chain.pem"
+//	export CERT_DOMAIN="cdn.example.com"
+//	go run main.go
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	// Load environment variables from .env file
+	if err := godotenv.Load(); err != nil {
+		log.Printf("⚠️ Warning: unable to load .env file: %v", err)
+	}
+
+	// Read API token
+	token := os.Getenv("CACHEFLY_API_TOKEN")
+	if token == "" {
+		log.Fatal("❌ CACHEFLY_API_TOKEN environment variable is required")
+	}
+
+	// Initialize CacheFly client
+	client := cachefly.NewClient(
+		cachefly.WithToken(token),
+	)
+
+	// Parse the certificate, chain and key locally
+	var chain []string
+	if f := os.Getenv("CHAIN_FILE"); f != "" {
+		chain = append(chain, f)
+	}
+	bundle, err := api.LoadCertificateBundle(os.Getenv("CERT_FILE"), os.Getenv("KEY_FILE"), chain...)
+	if err != nil {
+		log.Fatalf("❌ Failed to load certificate: %v", err)
+	}
+	fmt.Printf("🔐 Loaded certificate for %v, expires %s\n", bundle.Leaf.DNSNames, bundle.Leaf.NotAfter.Format(time.RFC3339))
+
+	// Check and upload; nothing is sent if a check fails
+	opts := api.CertificateCheckOptions{MinValidity: 7 * 24 * time.Hour}
+	if d := os.Getenv("CERT_DOMAIN"); d != "" {
+		opts.Domains = []string{d}
+	}
+	cert, err := client.Certificates.Upload(context.Background(), bundle, opts)
+	if errors.Is(err, api.ErrCertificateKeyMismatch) {
+		log.Fatal("❌ The private key does not belong to the certificate")
+	}
+	if err != nil {
+		log.Fatalf("❌ Failed to upload certificate: %v", err)
+	}
+
+	fmt.Printf("\n✅ Certificate %s uploaded for %s\n", cert.ID, cert.SubjectCommonName)
+}
@@ -0,0 +1,92 @@
+// Example demonstrates the full validation workflow of a service domain.
+//
+// This example shows:
+// - Printing the DNS records the domain needs
+// - Checking the records with the system resolver
+// - Requesting validation and waiting for the result
+//
+// Usage:
+//
+//	export CACHEFLY_API_TOKEN="your-token"
+//	go run main.go <service_id> <domain_id>
+//
+// Example:
+//
+//	go run main.go srv_123456789 dom_987654321
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	// Load environment variables from .env file
+	if err := godotenv.Load(); err != nil {
+		log.Printf("⚠️ Warning: unable to load .env file: %v", err)
+	}
+
+	// Read API token from environment
+	token := os.Getenv("CACHEFLY_API_TOKEN")
+	if token == "" {
+		log.Fatal("❌ CACHEFLY_API_TOKEN environment variable is required")
+	}
+
+	// Read service ID and domain ID arguments
+	if len(os.Args) < 3 {
+		log.Println("⚠️ Usage: go run main.go <service_id> <domain_id>")
+		return
+	}
+	serviceID := os.Args[1]
+	domainID := os.Args[2]
+
+	// Initialize CacheFly client
+	client := cachefly.NewClient(
+		cachefly.WithToken(token),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
+	defer cancel()
+
+	domain, err := client.ServiceDomains.GetByID(ctx, serviceID, domainID, "")
+	if err != nil {
+		log.Fatalf("❌ Failed to get domain %s: %v", domainID, err)
+	}
+
+	records, err := domain.ValidationRecords()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if len(records) > 0 {
+		fmt.Println("📋 Required DNS records:")
+		for _, r := range records {
+			fmt.Println("  ", r)
+		}
+	}
+	if challenge, err := domain.HTTPChallenge(); err == nil {
+		fmt.Printf("📋 %s must be served by CacheFly through %s\n", challenge.URL, challenge.Target)
+	}
+
+	domain, err = client.ServiceDomains.Validate(ctx, serviceID, domainID, api.ValidateDomainOptions{
+		Resolver: net.DefaultResolver,
+	})
+	var recErr *api.DomainRecordError
+	switch {
+	case errors.As(err, &recErr):
+		log.Fatalf("⏳ DNS is not ready yet: %v", err)
+	case err != nil:
+		log.Fatalf("❌ Validation of domain %s failed: %v", domainID, err)
+	}
+
+	fmt.Printf("✅ Domain %s is %s.\n", domain.Name, domain.ValidationStatus)
+}
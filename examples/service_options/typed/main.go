@@ -0,0 +1,75 @@
+// Example demonstrates reading and updating service options with typed structs
+// and discovering the available options from the option metadata.
+//
+// Usage:
+//
+//	export CACHEFLY_API_TOKEN="your-token"
+//	go run main.go <service_id>
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Printf("⚠️  Warning: unable to load .env file: %v", err)
+	}
+
+	token := os.Getenv("CACHEFLY_API_TOKEN")
+	if token == "" {
+		log.Fatal("❌ CACHEFLY_API_TOKEN environment variable is required")
+	}
+
+	if len(os.Args) < 2 {
+		log.Println("⚠️  Usage: go run main.go <service_id>")
+		return
+	}
+	serviceID := os.Args[1]
+
+	client := cachefly.NewClient(cachefly.WithToken(token))
+	ctx := context.Background()
+
+	// Every option the service supports, including dynamic options added to
+	// the platform after this SDK was released
+	registry, err := client.ServiceOptions.Registry(ctx, serviceID)
+	if err != nil {
+		log.Fatalf("❌ Failed to get options metadata: %v", err)
+	}
+	fmt.Println("📋 Available options:")
+	for _, key := range registry.Keys() {
+		opt, _ := registry.Lookup(key)
+		fmt.Printf("  %-24s %s (%s)\n", key, opt.Title, opt.Type)
+	}
+
+	current, err := client.ServiceOptions.GetTypedOptions(ctx, serviceID)
+	if err != nil {
+		log.Fatalf("❌ Failed to get service options: %v", err)
+	}
+	if current.ReverseProxy != nil {
+		fmt.Printf("🔁 Reverse proxy: enabled=%v hostname=%s\n", current.ReverseProxy.Enabled, current.ReverseProxy.Hostname)
+	}
+	for key, opt := range current.Dynamic {
+		fmt.Printf("⚙️  %s = %v (enabled=%v)\n", key, opt.Value, !opt.Switched || opt.Enabled)
+	}
+
+	// Only the options set here are changed
+	update := &api.TypedServiceOptions{
+		CORS:         &api.CORS{Enabled: true},
+		AutoRedirect: &api.AutoRedirect{Enabled: true},
+	}
+	if registry.IsDynamic("error_ttl") {
+		update.Dynamic = map[string]api.DynamicOption{"error_ttl": api.Switch(true, 300)}
+	}
+	if _, err := client.ServiceOptions.UpdateTypedOptions(ctx, serviceID, update); err != nil {
+		log.Fatalf("❌ Failed to update service options: %v", err)
+	}
+	fmt.Println("✅ Service options updated.")
+}
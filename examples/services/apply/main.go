@@ -0,0 +1,80 @@
+// Example demonstrates reconciling a service with a declarative spec file.
+//
+// This example shows:
+// - Loading a JSON service spec
+// - Printing the plan of changes
+// - Applying the plan only when -apply is given
+//
+// Usage:
+//
+//	export CACHEFLY_API_TOKEN="your-token"
+//	go run main.go [-apply] service.json
+//
+// Example spec:
+//
+//	{
+//	  "uniqueName": "my-service",
+//	  "domains": [{"name": "cdn.example.com"}],
+//	  "options": {"cors": true},
+//	  "logging": {"accessLogTarget": "my-s3-target", "originLogTarget": ""}
+//	}
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly/apply"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Printf("⚠️ Warning: unable to load .env file: %v", err)
+	}
+
+	token := os.Getenv("CACHEFLY_API_TOKEN")
+	if token == "" {
+		log.Fatal("❌ CACHEFLY_API_TOKEN environment variable is required")
+	}
+
+	doApply := flag.Bool("apply", false, "apply the plan instead of only printing it")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		log.Fatal("usage: go run main.go [-apply] service.json")
+	}
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("❌ Failed to open spec: %v", err)
+	}
+	defer f.Close()
+
+	spec, err := apply.LoadSpec(f)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	client := cachefly.NewClient(cachefly.WithToken(token))
+	engine := apply.New(client)
+	ctx := context.Background()
+
+	plan, err := engine.Plan(ctx, *spec)
+	if err != nil {
+		log.Fatalf("❌ Failed to plan: %v", err)
+	}
+	fmt.Print(plan)
+
+	if !*doApply || plan.Empty() {
+		return
+	}
+	if err := engine.Apply(ctx, plan); err != nil {
+		log.Fatalf("❌ Failed to apply: %v", err)
+	}
+	fmt.Println("\n ✅ Service updated")
+}
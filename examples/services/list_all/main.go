@@ -0,0 +1,55 @@
+// Example demonstrates iterating over every CacheFly service page by page.
+//
+// This example shows:
+// - Client initialization with API token
+// - Ranging over the Services.All iterator with a custom page size
+// - Collecting every service with ListAll
+//
+// Usage:
+//
+//	export CACHEFLY_API_TOKEN="your-token"
+//	go run main.go
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Printf("⚠️ Warning: unable to load .env file: %v", err)
+	}
+
+	token := os.Getenv("CACHEFLY_API_TOKEN")
+	if token == "" {
+		log.Fatal("❌ CACHEFLY_API_TOKEN environment variable is required")
+	}
+
+	client := cachefly.NewClient(
+		cachefly.WithToken(token),
+	)
+	ctx := context.Background()
+
+	// Pages of 25 services are fetched as the loop advances.
+	for svc, err := range client.Services.All(ctx, api.ListOptions{Limit: 25, Status: "ACTIVE"}) {
+		if err != nil {
+			log.Fatalf("❌ Failed to list services: %v", err)
+		}
+		fmt.Printf("%s\t%s\n", svc.ID, svc.UniqueName)
+	}
+
+	// Or collect every service at once.
+	all, err := api.ListAll(client.Services.All(ctx, api.ListOptions{}))
+	if err != nil {
+		log.Fatalf("❌ Failed to list services: %v", err)
+	}
+	fmt.Printf("\n ✅ %d services in total\n", len(all))
+}
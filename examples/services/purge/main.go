@@ -3,7 +3,7 @@
 // Usage:
 //
 //	export CACHEFLY_API_TOKEN="your-token"
-//	go run main.go <service_id> [all]
+//	go run main.go <service_id> [all | <url-or-path>...]
 package main
 
 import (
@@ -28,7 +28,7 @@ func main() {
 	}
 
 	if len(os.Args) < 2 {
-		log.Println("⚠️ Usage: go run main.go <service_id> [all]")
+		log.Println("⚠️ Usage: go run main.go <service_id> [all | <url-or-path>...]")
 		return
 	}
 	serviceID := os.Args[1]
@@ -37,22 +37,29 @@ func main() {
 	client := cachefly.NewClient(cachefly.WithToken(token))
 	ctx := context.Background()
 
-	var req api.PurgeRequest
 	if purgeAll {
-		req = api.PurgeRequest{All: true}
-	} else {
-		// Example of purging specific objects/directories
-		req = api.PurgeRequest{Paths: []string{"/index.html", "/images/"}}
+		fmt.Println("🔄 Submitting purge-all request...")
+		if err := client.Services.Purge(ctx, serviceID, api.PurgeRequest{All: true}); err != nil {
+			log.Fatalf("❌ Failed to purge service cache: %v", err)
+		}
+		fmt.Println("✅ Purge-all request accepted.")
+		return
 	}
 
-	fmt.Println("🔄 Submitting purge request...")
-	if err := client.Services.Purge(ctx, serviceID, req); err != nil {
-		log.Fatalf("❌ Failed to purge service cache: %v", err)
+	// Full URLs on the service's domains and plain paths can be mixed; they are
+	// normalized, de-duplicated and sent in batches.
+	targets := os.Args[2:]
+	if len(targets) == 0 {
+		targets = []string{"/index.html", "/images/"}
 	}
 
-	if purgeAll {
-		fmt.Println("✅ Purge-all request accepted.")
-	} else {
-		fmt.Println("✅ Purge-paths request accepted.")
+	fmt.Printf("🔄 Purging %d URLs and paths...\n", len(targets))
+	report, err := client.Services.PurgeURLs(ctx, serviceID, targets, api.PurgeOptions{})
+	if err != nil {
+		log.Fatalf("❌ Failed to purge service cache: %v", err)
+	}
+	for _, res := range report.Failed() {
+		fmt.Printf("❌ %s: %v\n", res.Input, res.Err)
 	}
+	fmt.Printf("✅ Purged %d of %d paths in %d requests.\n", report.Purged(), len(report.Results), report.Batches)
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
 	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
@@ -28,8 +29,8 @@ func main() {
 
 	opts := api.StatsQueryOptions{
 		Limit:   10,
-		From:    "2024-09-01",
-		To:      "2025-09-02",
+		From:    time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+		To:      time.Date(2025, 9, 2, 0, 0, 0, 0, time.UTC),
 		GroupBy: []string{"pop", "date"},
 	}
 	resp, err := client.ServiceStats.POP(context.Background(), sid, opts)
@@ -0,0 +1,157 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheResource names a group of rarely-changing endpoints whose GET
+// responses may be cached.
+type CacheResource string
+
+const (
+	// CacheOptionsMetadata is /services/{id}/options/metadata.
+	CacheOptionsMetadata CacheResource = "optionsMetadata"
+	// CacheTLSProfiles is /tlsprofiles and /tlsprofiles/{id}.
+	CacheTLSProfiles CacheResource = "tlsProfiles"
+	// CacheDeliveryRegions is /deliveryregions.
+	CacheDeliveryRegions CacheResource = "deliveryRegions"
+	// CacheScriptDefinitions is /scriptConfigDefinitions and its sub-resources.
+	CacheScriptDefinitions CacheResource = "scriptDefinitions"
+	// CacheSchemas is the rules, image optimization and script config schemas,
+	// and the default image optimization configuration.
+	CacheSchemas CacheResource = "schemas"
+)
+
+// CachePolicy sets how long the responses of each resource are cached.
+// Resources without a positive TTL are not cached.
+type CachePolicy struct {
+	TTLs map[CacheResource]time.Duration
+}
+
+// DefaultCachePolicy caches metadata and schemas for 15 minutes, and TLS
+// profiles, delivery regions and script definitions for an hour.
+func DefaultCachePolicy() CachePolicy {
+	return CachePolicy{TTLs: map[CacheResource]time.Duration{
+		CacheOptionsMetadata:   15 * time.Minute,
+		CacheSchemas:           15 * time.Minute,
+		CacheTLSProfiles:       time.Hour,
+		CacheDeliveryRegions:   time.Hour,
+		CacheScriptDefinitions: time.Hour,
+	}}
+}
+
+// Cache is a read-through cache of GET responses, keyed by endpoint. Only
+// successful responses are cached.
+type Cache struct {
+	mu      sync.Mutex
+	ttls    map[CacheResource]time.Duration
+	entries map[string]cacheEntry
+	now     func() time.Time
+}
+
+type cacheEntry struct {
+	resource CacheResource
+	data     []byte
+	expires  time.Time
+}
+
+// NewCache returns an empty cache using policy.
+func NewCache(policy CachePolicy) *Cache {
+	ttls := make(map[CacheResource]time.Duration, len(policy.TTLs))
+	for r, ttl := range policy.TTLs {
+		if ttl > 0 {
+			ttls[r] = ttl
+		}
+	}
+	return &Cache{ttls: ttls, entries: make(map[string]cacheEntry), now: time.Now}
+}
+
+// Invalidate drops the cached responses of the given resources, or of every
+// resource when none is given.
+func (c *Cache) Invalidate(resources ...CacheResource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(resources) == 0 {
+		clear(c.entries)
+		return
+	}
+	for key, e := range c.entries {
+		for _, r := range resources {
+			if e.resource == r {
+				delete(c.entries, key)
+			}
+		}
+	}
+}
+
+func (c *Cache) get(endpoint string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[endpoint]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(e.expires) {
+		delete(c.entries, endpoint)
+		return nil, false
+	}
+	return e.data, true
+}
+
+func (c *Cache) set(endpoint string, resource CacheResource, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[endpoint] = cacheEntry{resource: resource, data: data, expires: c.now().Add(c.ttls[resource])}
+}
+
+// cachedGet serves a GET from the cache when the endpoint belongs to a cached
+// resource, fetching and storing the response on a miss. It reports false
+// when the endpoint is not cached.
+func (c *Client) cachedGet(ctx context.Context, endpoint string, out interface{}) (bool, error) {
+	resource, ok := cacheResource(endpoint)
+	if !ok || c.cache.ttls[resource] <= 0 {
+		return false, nil
+	}
+
+	data, hit := c.cache.get(endpoint)
+	if !hit {
+		var raw json.RawMessage
+		if err := c.do(ctx, http.MethodGet, endpoint, nil, &raw); err != nil {
+			return true, err
+		}
+		data = raw
+		c.cache.set(endpoint, resource, data)
+	}
+	if out == nil {
+		return true, nil
+	}
+	return true, json.Unmarshal(data, out)
+}
+
+// cacheResource returns the cached resource an endpoint belongs to.
+func cacheResource(endpoint string) (CacheResource, bool) {
+	p, _, _ := strings.Cut(endpoint, "?")
+	parts := strings.Split(strings.Trim(p, "/"), "/")
+	switch {
+	case parts[0] == "tlsprofiles":
+		return CacheTLSProfiles, true
+	case parts[0] == "deliveryregions":
+		return CacheDeliveryRegions, true
+	case parts[0] == "scriptConfigDefinitions":
+		return CacheScriptDefinitions, true
+	case len(parts) == 3 && parts[0] == "scriptConfigs" && parts[2] == "schema":
+		return CacheSchemas, true
+	case len(parts) == 4 && parts[0] == "services" && parts[2] == "options" && parts[3] == "metadata":
+		return CacheOptionsMetadata, true
+	case len(parts) == 4 && parts[0] == "services" && parts[2] == "rules" && parts[3] == "schema":
+		return CacheSchemas, true
+	case len(parts) == 4 && parts[0] == "services" && parts[2] == "imageopt4" && (parts[3] == "schema" || parts[3] == "default"):
+		return CacheSchemas, true
+	}
+	return "", false
+}
@@ -0,0 +1,112 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCache_ReadThrough(t *testing.T) {
+	hits := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits[r.URL.Path]++
+		if r.URL.Path == "/deliveryregions" && hits[r.URL.Path] == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"name":"` + r.URL.Path + `"}`))
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	cache := NewCache(CachePolicy{TTLs: map[CacheResource]time.Duration{
+		CacheTLSProfiles:     time.Minute,
+		CacheDeliveryRegions: time.Minute,
+		CacheSchemas:         time.Hour,
+	}})
+	cache.now = clock.now
+	c := New(Config{BaseURL: server.URL, Cache: cache})
+	ctx := context.Background()
+
+	get := func(endpoint string) string {
+		t.Helper()
+		var out struct{ Name string }
+		if err := c.Get(ctx, endpoint, &out); err != nil {
+			t.Fatalf("Expected no error for %s, got %v", endpoint, err)
+		}
+		return out.Name
+	}
+
+	for i := 0; i < 3; i++ {
+		if name := get("/tlsprofiles?limit=10"); name != "/tlsprofiles" {
+			t.Errorf("Unexpected response %q", name)
+		}
+		get("/services/svc-1/rules/schema")
+		get("/services/svc-1")
+	}
+	if hits["/tlsprofiles"] != 1 || hits["/services/svc-1/rules/schema"] != 1 {
+		t.Errorf("Expected cached resources to be fetched once, got %v", hits)
+	}
+	if hits["/services/svc-1"] != 3 {
+		t.Errorf("Expected other endpoints not to be cached, got %v", hits)
+	}
+
+	// A different query is a different entry.
+	get("/tlsprofiles?limit=20")
+	if hits["/tlsprofiles"] != 2 {
+		t.Errorf("Expected a new query to be fetched, got %v", hits)
+	}
+
+	// Errors are not cached.
+	if err := c.Get(ctx, "/deliveryregions", nil); err == nil {
+		t.Fatal("Expected the first delivery regions request to fail")
+	}
+	get("/deliveryregions")
+	get("/deliveryregions")
+	if hits["/deliveryregions"] != 2 {
+		t.Errorf("Expected one retry after the error and a cache hit, got %v", hits)
+	}
+
+	// Entries expire after their resource TTL.
+	clock.advance(2 * time.Minute)
+	get("/tlsprofiles?limit=10")
+	get("/services/svc-1/rules/schema")
+	if hits["/tlsprofiles"] != 3 || hits["/services/svc-1/rules/schema"] != 1 {
+		t.Errorf("Expected only expired entries to be refetched, got %v", hits)
+	}
+
+	cache.Invalidate(CacheSchemas)
+	get("/services/svc-1/rules/schema")
+	get("/tlsprofiles?limit=10")
+	if hits["/services/svc-1/rules/schema"] != 2 || hits["/tlsprofiles"] != 3 {
+		t.Errorf("Expected only the invalidated resource to be refetched, got %v", hits)
+	}
+
+	cache.Invalidate()
+	get("/tlsprofiles?limit=10")
+	if hits["/tlsprofiles"] != 4 {
+		t.Errorf("Expected everything to be refetched after invalidating all, got %v", hits)
+	}
+}
+
+func TestCacheResource(t *testing.T) {
+	tests := map[string]CacheResource{
+		"/services/svc-1/options/metadata":  CacheOptionsMetadata,
+		"/tlsprofiles/tp-1":                 CacheTLSProfiles,
+		"/deliveryregions?limit=100":        CacheDeliveryRegions,
+		"/scriptConfigDefinitions/def-1":    CacheScriptDefinitions,
+		"/scriptConfigs/sc-1/schema":        CacheSchemas,
+		"/services/svc-1/imageopt4/default": CacheSchemas,
+		"/services/svc-1/options":           "",
+		"/services/svc-1/imageopt4":         "",
+		"/scriptConfigs/sc-1":               "",
+	}
+	for endpoint, want := range tests {
+		got, ok := cacheResource(endpoint)
+		if got != want || ok != (want != "") {
+			t.Errorf("cacheResource(%q) = %q, %v; want %q", endpoint, got, ok, want)
+		}
+	}
+}
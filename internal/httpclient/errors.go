@@ -0,0 +1,173 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Sentinel errors matched by *APIError through errors.Is.
+var (
+	ErrBadRequest   = errors.New("cachefly: bad request")
+	ErrUnauthorized = errors.New("cachefly: unauthorized")
+	ErrForbidden    = errors.New("cachefly: forbidden")
+	ErrNotFound     = errors.New("cachefly: not found")
+	ErrConflict     = errors.New("cachefly: conflict")
+	ErrValidation   = errors.New("cachefly: validation failed")
+	ErrRateLimited  = errors.New("cachefly: rate limited")
+	ErrServer       = errors.New("cachefly: server error")
+)
+
+// requestIDHeaders lists the response headers checked, in order, for a request identifier.
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "X-Amzn-Requestid", "X-Amzn-Trace-Id"}
+
+// FieldError describes a problem with a single request field reported by the API.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+}
+
+// APIError is returned for every API response with a status code of 400 or above.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Method is the HTTP method of the failed request.
+	Method string
+	// URL is the full request URL.
+	URL string
+	// RequestID is the request identifier returned by the API, if any.
+	RequestID string
+	// Header holds the response headers.
+	Header http.Header
+	// Body is the raw response body.
+	Body []byte
+	// Message is the error message decoded from the response body.
+	Message string
+	// Code is the machine readable error code decoded from the response body, if any.
+	Code string
+	// Errors holds per-field validation errors decoded from the response body.
+	Errors []FieldError
+}
+
+func (e *APIError) Error() string {
+	detail := e.Message
+	if detail == "" {
+		detail = string(e.Body)
+	}
+	if len(e.Errors) > 0 {
+		parts := make([]string, 0, len(e.Errors))
+		for _, fe := range e.Errors {
+			if fe.Field != "" {
+				parts = append(parts, fe.Field+": "+fe.Message)
+			} else {
+				parts = append(parts, fe.Message)
+			}
+		}
+		detail = fmt.Sprintf("%s (%s)", detail, strings.Join(parts, "; "))
+	}
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, detail)
+}
+
+// Is reports whether the error matches one of the package sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrValidation:
+		return e.StatusCode == http.StatusUnprocessableEntity
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// errorPayload covers the error body shapes returned by the CacheFly API.
+type errorPayload struct {
+	Message json.RawMessage `json:"message"`
+	Error   json.RawMessage `json:"error"`
+	Code    json.RawMessage `json:"code"`
+	Errors  json.RawMessage `json:"errors"`
+}
+
+func newAPIError(req *http.Request, resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		Header:     resp.Header,
+		Body:       body,
+	}
+	for _, h := range requestIDHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			apiErr.RequestID = v
+			break
+		}
+	}
+
+	var payload errorPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return apiErr
+	}
+
+	// message may be a string or, for validation failures, a list of strings
+	var messages []string
+	if msg := rawString(payload.Message); msg != "" {
+		apiErr.Message = msg
+	} else if err := json.Unmarshal(payload.Message, &messages); err == nil && len(messages) > 0 {
+		apiErr.Message = strings.Join(messages, "; ")
+	} else {
+		apiErr.Message = rawString(payload.Error)
+	}
+	apiErr.Code = rawString(payload.Code)
+
+	if len(payload.Errors) > 0 {
+		var fieldErrs []FieldError
+		if err := json.Unmarshal(payload.Errors, &fieldErrs); err == nil {
+			apiErr.Errors = fieldErrs
+		} else {
+			var byField map[string]string
+			if err := json.Unmarshal(payload.Errors, &byField); err == nil {
+				fields := make([]string, 0, len(byField))
+				for field := range byField {
+					fields = append(fields, field)
+				}
+				sort.Strings(fields)
+				for _, field := range fields {
+					apiErr.Errors = append(apiErr.Errors, FieldError{Field: field, Message: byField[field]})
+				}
+			}
+		}
+	}
+
+	return apiErr
+}
+
+// rawString returns the JSON string or number in raw as text, or "" for other values.
+func rawString(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n.String()
+	}
+	return ""
+}
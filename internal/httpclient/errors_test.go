@@ -0,0 +1,91 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIError_DecodesPayload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-42")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"message":"Validation failed","code":"VALIDATION_ERROR","errors":[{"field":"name","message":"name is required"}]}`))
+	}))
+	defer server.Close()
+
+	client := New(Config{BaseURL: server.URL + "/api/2.6", AuthToken: "t"})
+	err := client.Post(context.Background(), "/services", struct{}{}, nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422, got %d", apiErr.StatusCode)
+	}
+	if apiErr.Method != http.MethodPost {
+		t.Errorf("expected method POST, got %s", apiErr.Method)
+	}
+	if apiErr.URL != server.URL+"/api/2.6/services" {
+		t.Errorf("unexpected URL %s", apiErr.URL)
+	}
+	if apiErr.RequestID != "req-42" {
+		t.Errorf("expected request ID req-42, got %s", apiErr.RequestID)
+	}
+	if apiErr.Message != "Validation failed" || apiErr.Code != "VALIDATION_ERROR" {
+		t.Errorf("unexpected message/code: %q %q", apiErr.Message, apiErr.Code)
+	}
+	if len(apiErr.Errors) != 1 || apiErr.Errors[0].Field != "name" {
+		t.Errorf("unexpected field errors: %+v", apiErr.Errors)
+	}
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("expected errors.Is(err, ErrValidation)")
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Errorf("did not expect errors.Is(err, ErrNotFound)")
+	}
+}
+
+func TestAPIError_MessageList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"statusCode":400,"message":["name must be a string","limit must be positive"],"error":"Bad Request"}`))
+	}))
+	defer server.Close()
+
+	client := New(Config{BaseURL: server.URL, AuthToken: "t"})
+	err := client.Get(context.Background(), "/services", nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+	if apiErr.Message != "name must be a string; limit must be positive" {
+		t.Errorf("unexpected message %q", apiErr.Message)
+	}
+	if !errors.Is(err, ErrBadRequest) {
+		t.Errorf("expected errors.Is(err, ErrBadRequest)")
+	}
+}
+
+func TestAPIError_NonJSONBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+	}))
+	defer server.Close()
+
+	client := New(Config{BaseURL: server.URL, AuthToken: "t"})
+	err := client.Delete(context.Background(), "/services/missing", nil)
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected errors.Is(err, ErrNotFound), got %v", err)
+	}
+	if err.Error() != "API error 404: not found" {
+		t.Errorf("unexpected error string %q", err.Error())
+	}
+}
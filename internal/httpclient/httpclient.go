@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"path"
 	"time"
@@ -14,21 +15,70 @@ import (
 type Config struct {
 	BaseURL   string
 	AuthToken string
+
+	// TokenProvider supplies the token for each request, overriding AuthToken.
+	TokenProvider TokenProvider
+
+	// RetryPolicy enables automatic retries when set.
+	RetryPolicy *RetryPolicy
+
+	// RateLimiter throttles outgoing requests when set.
+	RateLimiter *RateLimiter
+
+	// Cache serves GET requests of rarely-changing resources when set.
+	Cache *Cache
+
+	// HTTPClient is used to send requests. It is copied, not modified.
+	// Defaults to a client with DefaultTimeout.
+	HTTPClient *http.Client
+
+	// Timeout overrides the timeout of HTTPClient when positive.
+	Timeout time.Duration
+
+	// UserAgent overrides DefaultUserAgent.
+	UserAgent string
+
+	// Middleware wraps the transport of HTTPClient, the first entry being the outermost.
+	Middleware []Middleware
+
+	// Instrumenter observes every API call when set.
+	Instrumenter Instrumenter
+
+	// Logger logs every request when set, with secrets redacted.
+	Logger *slog.Logger
 }
 
 type Client struct {
-	http    *http.Client
-	baseURL string
-	token   string
+	http      *http.Client
+	baseURL   string
+	tokens    TokenProvider
+	userAgent string
+	retry     *RetryPolicy
+	limiter   *RateLimiter
+	cache     *Cache
+
+	instrumenter Instrumenter
 }
 
 func New(cfg Config) *Client {
+	userAgent := cfg.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	tokens := cfg.TokenProvider
+	if tokens == nil {
+		tokens = StaticToken(cfg.AuthToken)
+	}
 	return &Client{
-		http: &http.Client{
-			Timeout: 35 * time.Second,
-		},
-		baseURL: cfg.BaseURL,
-		token:   cfg.AuthToken,
+		http:      buildHTTPClient(cfg),
+		baseURL:   cfg.BaseURL,
+		tokens:    tokens,
+		userAgent: userAgent,
+		retry:     cfg.RetryPolicy,
+		limiter:   cfg.RateLimiter,
+		cache:     cfg.Cache,
+
+		instrumenter: cfg.Instrumenter,
 	}
 }
 
@@ -38,119 +88,165 @@ func (c *Client) Post(ctx context.Context, endpoint string, body interface{}, ou
 	if err != nil {
 		return fmt.Errorf("failed to marshal request body: %w", err)
 	}
+	return c.do(ctx, http.MethodPost, endpoint, payload, out)
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.fullURL(endpoint), bytes.NewReader(payload))
-	if err != nil {
-		return err
+// Get performs a GET request and decodes the JSON response, serving it from
+// the cache when the endpoint is cached.
+func (c *Client) Get(ctx context.Context, endpoint string, out interface{}) error {
+	if c.cache != nil {
+		if cached, err := c.cachedGet(ctx, endpoint, out); cached {
+			return err
+		}
 	}
+	return c.do(ctx, http.MethodGet, endpoint, nil, out)
+}
 
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return err
+// Put performs a PUT request with an optional JSON payload and decodes the JSON response into out.
+func (c *Client) Put(ctx context.Context, endpoint string, body interface{}, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
 	}
-	defer resp.Body.Close()
+	return c.do(ctx, http.MethodPut, endpoint, payload, out)
+}
 
-	if resp.StatusCode >= 400 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
-	}
+// Delete performs a DELETE request with no body and decodes the JSON response into out.
+func (c *Client) Delete(ctx context.Context, endpoint string, out interface{}) error {
+	return c.do(ctx, http.MethodDelete, endpoint, nil, out)
+}
 
-	return json.NewDecoder(resp.Body).Decode(out)
+// do sends an API request, retrying it according to the retry policy, and
+// decodes the JSON response into out when out is non-nil. Responses with a
+// status of 400 or above are returned as *APIError.
+func (c *Client) do(ctx context.Context, method, endpoint string, payload []byte, out interface{}) error {
+	if c.instrumenter == nil {
+		res, _ := c.send(ctx, method, endpoint, payload, out)
+		return res.err
+	}
+
+	start := time.Now()
+	ctx, end := c.instrumenter.StartCall(ctx, callInfo(method, endpoint))
+	res, attempts := c.send(ctx, method, endpoint, payload, out)
+	end(CallResult{
+		StatusCode: res.statusCode,
+		Retries:    attempts - 1,
+		Duration:   time.Since(start),
+		Err:        res.err,
+	})
+	return res.err
 }
 
-// Get performs a GET request and decodes the JSON response.
-func (c *Client) Get(ctx context.Context, endpoint string, out interface{}) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.fullURL(endpoint), nil)
-	if err != nil {
-		return err
+// send performs the attempts of a request and returns the result of the last
+// one and the number of attempts made.
+func (c *Client) send(ctx context.Context, method, endpoint string, payload []byte, out interface{}) (attemptResult, int) {
+	for attempt := 1; ; attempt++ {
+		res := c.attempt(ctx, method, endpoint, payload, out)
+		if res.err == nil {
+			return res, attempt
+		}
+
+		delay, ok := c.retryDelay(ctx, method, attempt, res)
+		if !ok {
+			return res, attempt
+		}
+		if c.retry.OnRetry != nil {
+			c.retry.OnRetry(RetryEvent{
+				Attempt:    attempt,
+				Method:     method,
+				URL:        c.fullURL(endpoint),
+				StatusCode: res.statusCode,
+				Err:        res.err,
+				Delay:      delay,
+			})
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return res, attempt
+		}
 	}
+}
 
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Accept", "application/json")
+// attemptResult describes the outcome of a single HTTP round trip.
+type attemptResult struct {
+	err        error
+	statusCode int           // 0 when no response was received
+	retryAfter time.Duration // Retry-After advertised by the response
+	transport  bool          // the request failed before a response was received
+}
 
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return err
+// attempt performs a single HTTP round trip.
+func (c *Client) attempt(ctx context.Context, method, endpoint string, payload []byte, out interface{}) attemptResult {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return attemptResult{err: err}
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return attemptResult{err: fmt.Errorf("failed to get API token: %w", err)}
 	}
 
-	return json.NewDecoder(resp.Body).Decode(out)
-}
-
-func (c *Client) Put(ctx context.Context, endpoint string, body interface{}, out interface{}) error {
-	// 1. Marshal the request body
 	var reader io.Reader
-	if body != nil {
-		payload, err := json.Marshal(body)
-		if err != nil {
-			return fmt.Errorf("failed to marshal request body: %w", err)
-		}
+	if payload != nil {
 		reader = bytes.NewReader(payload)
 	}
 
-	// 2. Create the request
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.fullURL(endpoint), reader)
+	req, err := http.NewRequestWithContext(ctx, method, c.fullURL(endpoint), reader)
 	if err != nil {
-		return err
+		return attemptResult{err: err}
 	}
 
-	// 3. Set headers
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if method == http.MethodPost || method == http.MethodPut {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
-	// 4. Do the call
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return err
+		return attemptResult{err: err, transport: true}
 	}
 	defer resp.Body.Close()
 
-	// 5. Check for error status
+	if c.limiter != nil {
+		c.limiter.Observe(resp)
+	}
+
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return attemptResult{
+			err:        newAPIError(req, resp, body),
+			statusCode: resp.StatusCode,
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
 	}
 
-	// 6. Decode if out is provided
+	res := attemptResult{statusCode: resp.StatusCode}
 	if out != nil {
-		return json.NewDecoder(resp.Body).Decode(out)
+		res.err = json.NewDecoder(resp.Body).Decode(out)
 	}
-	return nil
+	return res
 }
 
-// Delete performs a DELETE request with no body and decodes the JSON response into out.
-func (c *Client) Delete(ctx context.Context, endpoint string, out interface{}) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.fullURL(endpoint), nil)
-	if err != nil {
-		return err
+// retryDelay reports whether a failed attempt should be retried and how long
+// to wait before doing so.
+func (c *Client) retryDelay(ctx context.Context, method string, attempt int, res attemptResult) (time.Duration, bool) {
+	if c.retry == nil || attempt >= c.retry.MaxAttempts || ctx.Err() != nil {
+		return 0, false
 	}
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return err
+	if !c.retry.allowsMethod(method) {
+		return 0, false
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
-	}
-	if out != nil {
-		return json.NewDecoder(resp.Body).Decode(out)
+	if !res.transport && !(res.statusCode >= 400 && c.retry.retryableStatus(res.statusCode)) {
+		return 0, false
 	}
-	return nil
+	return c.retry.backoff(attempt, res.retryAfter), true
 }
 
 func (c *Client) fullURL(endpoint string) string {
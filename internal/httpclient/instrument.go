@@ -0,0 +1,118 @@
+package httpclient
+
+import (
+	"context"
+	"net/url"
+	"runtime"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Instrumenter observes API calls, e.g. to trace them or record metrics.
+type Instrumenter interface {
+	// StartCall is called before the first attempt of a call. The returned
+	// context is used for the call's requests and end is called once with
+	// the outcome of the call.
+	StartCall(ctx context.Context, call CallInfo) (_ context.Context, end func(CallResult))
+}
+
+// CallInfo describes an API call.
+type CallInfo struct {
+	// Operation is the service group and method that made the call, e.g.
+	// "ServiceOptions.UpdateOptions", or empty if it cannot be determined.
+	Operation string
+	Method    string
+	// Path is the endpoint path, without the base URL and query.
+	Path string
+	// ResourceIDs maps resource types found in the path, such as "service"
+	// or "domain", to their IDs.
+	ResourceIDs map[string]string
+}
+
+// CallResult is the outcome of an API call.
+type CallResult struct {
+	// StatusCode of the last response, or 0 if none was received.
+	StatusCode int
+	// Retries is the number of attempts after the first one.
+	Retries  int
+	Duration time.Duration
+	Err      error
+}
+
+// resourceTypes maps path segments followed by an ID to resource types.
+var resourceTypes = map[string]string{
+	"accounts":                "account",
+	"cachewarming":            "cache_warming_task",
+	"certificates":            "certificate",
+	"domains":                 "domain",
+	"logtargets":              "log_target",
+	"origins":                 "origin",
+	"refererrules":            "referer_rule",
+	"saml":                    "saml",
+	"scriptConfigDefinitions": "script_definition",
+	"scriptConfigs":           "script_config",
+	"services":                "service",
+	"tlsprofiles":             "tls_profile",
+	"users":                   "user",
+}
+
+// callInfo describes a call to endpoint made from the caller of do.
+func callInfo(method, endpoint string) CallInfo {
+	path := endpoint
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	info := CallInfo{Operation: operationName(), Method: method, Path: path}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		typ, ok := resourceTypes[segments[i]]
+		if !ok || segments[i+1] == "me" || segments[i+1] == "promo" {
+			continue
+		}
+		id, err := url.PathUnescape(segments[i+1])
+		if err != nil {
+			id = segments[i+1]
+		}
+		if info.ResourceIDs == nil {
+			info.ResourceIDs = map[string]string{}
+		}
+		info.ResourceIDs[typ] = id
+		i++
+	}
+	return info
+}
+
+// operationName returns the innermost exported method of a *XxxService type
+// on the call stack as "Xxx.Method".
+func operationName() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if op, ok := serviceMethod(frame.Function); ok {
+			return op
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// serviceMethod parses a function name such as
+// "example.com/api.(*ServiceOptionsService).UpdateOptions".
+func serviceMethod(function string) (string, bool) {
+	_, rest, ok := strings.Cut(function, ".(*")
+	if !ok {
+		return "", false
+	}
+	typ, method, ok := strings.Cut(rest, ").")
+	if !ok || !strings.HasSuffix(typ, "Service") || strings.Contains(method, ".") {
+		return "", false
+	}
+	if method == "" || !unicode.IsUpper(rune(method[0])) {
+		return "", false
+	}
+	return strings.TrimSuffix(typ, "Service") + "." + method, true
+}
@@ -0,0 +1,82 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type recordingInstrumenter struct {
+	calls   []CallInfo
+	results []CallResult
+}
+
+func (r *recordingInstrumenter) StartCall(ctx context.Context, call CallInfo) (context.Context, func(CallResult)) {
+	r.calls = append(r.calls, call)
+	return ctx, func(res CallResult) { r.results = append(r.results, res) }
+}
+
+type widgetsService struct {
+	client *Client
+}
+
+func (s *widgetsService) UpdateWidget(ctx context.Context, sid, id string) error {
+	return s.update(ctx, "/services/"+sid+"/domains/"+id+"?responseType=shallow")
+}
+
+func (s *widgetsService) update(ctx context.Context, endpoint string) error {
+	return s.client.Put(ctx, endpoint, nil, nil)
+}
+
+func TestInstrumenter(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts++; attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	rec := &recordingInstrumenter{}
+	client := New(Config{
+		BaseURL:      server.URL,
+		AuthToken:    "t",
+		RetryPolicy:  fastRetryPolicy(),
+		Instrumenter: rec,
+	})
+	svc := &widgetsService{client: client}
+	if err := svc.UpdateWidget(context.Background(), "svc 1", "d1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(rec.calls) != 1 || len(rec.results) != 1 {
+		t.Fatalf("expected one call, got %d calls and %d results", len(rec.calls), len(rec.results))
+	}
+	call := rec.calls[0]
+	if call.Operation != "widgets.UpdateWidget" || call.Method != http.MethodPut || call.Path != "/services/svc 1/domains/d1" {
+		t.Errorf("unexpected call %+v", call)
+	}
+	if call.ResourceIDs["service"] != "svc 1" || call.ResourceIDs["domain"] != "d1" {
+		t.Errorf("unexpected resource IDs %v", call.ResourceIDs)
+	}
+	if res := rec.results[0]; res.StatusCode != http.StatusNoContent || res.Retries != 1 || res.Err != nil {
+		t.Errorf("unexpected result %+v", res)
+	}
+}
+
+func TestServiceMethod(t *testing.T) {
+	tests := map[string]string{
+		"github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6.(*ServiceOptionsService).UpdateOptions": "ServiceOptions.UpdateOptions",
+		"github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6.(*ServiceOptionsService).updateOptions": "",
+		"github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6.(*ServicesService).All.func1":           "",
+		"github.com/cachefly/cachefly-sdk-go/internal/httpclient.(*Client).Get":                            "",
+	}
+	for function, want := range tests {
+		if got, _ := serviceMethod(function); got != want {
+			t.Errorf("serviceMethod(%q) = %q, expected %q", function, got, want)
+		}
+	}
+}
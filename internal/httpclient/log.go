@@ -0,0 +1,170 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Redacted replaces secret values in log records.
+const Redacted = "[REDACTED]"
+
+// maxLoggedBody is the number of bytes of a body included in a log record.
+const maxLoggedBody = 8 << 10
+
+// secretHeaders are the canonical names of headers whose values are never logged.
+var secretHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+}
+
+// secretFields are the lowercased JSON field names whose values are never
+// logged: origin and log target credentials, the FTP password, the
+// ProtectServe key, account and user passwords, child account tokens and
+// certificate private keys.
+var secretFields = map[string]bool{
+	"accesskey":       true,
+	"secretkey":       true,
+	"password":        true,
+	"apikey":          true,
+	"jsonkey":         true,
+	"ftppassword":     true,
+	"protectservekey": true,
+	"token":           true,
+	"certificatekey":  true,
+	"privatekey":      true,
+}
+
+// loggingTransport logs every round trip to logger: method, path, status and
+// duration at info level, failures at warn level and, when debug is enabled,
+// the headers and bodies with secrets redacted.
+func loggingTransport(logger *slog.Logger, next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		ctx := req.Context()
+		debug := logger.Enabled(ctx, slog.LevelDebug)
+
+		var reqBody []byte
+		if debug && req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				reqBody, _ = io.ReadAll(body)
+				body.Close()
+			}
+		}
+
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		duration := time.Since(start)
+		if err != nil {
+			logger.LogAttrs(ctx, slog.LevelWarn, "cachefly api request failed",
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+				slog.Duration("duration", duration),
+				slog.String("error", err.Error()),
+			)
+			return resp, err
+		}
+
+		if debug {
+			respBody, readErr := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(respBody), errReader{readErr}))
+
+			logger.LogAttrs(ctx, slog.LevelDebug, "cachefly api exchange",
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+				slog.Int("status", resp.StatusCode),
+				slog.Any("request_headers", redactHeaders(req.Header)),
+				slog.String("request_body", redactBody(reqBody)),
+				slog.Any("response_headers", redactHeaders(resp.Header)),
+				slog.String("response_body", redactBody(respBody)),
+			)
+		}
+		logger.LogAttrs(ctx, slog.LevelInfo, "cachefly api request",
+			slog.String("method", req.Method),
+			slog.String("path", req.URL.Path),
+			slog.Int("status", resp.StatusCode),
+			slog.Duration("duration", duration),
+		)
+		return resp, nil
+	})
+}
+
+// errReader returns err, or io.EOF when err is nil, so that a body read
+// in full for logging fails the same way for the caller.
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
+
+// redactHeaders returns the headers as a group with secret values replaced.
+func redactHeaders(h http.Header) slog.Value {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attrs := make([]slog.Attr, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(h[name], ", ")
+		if secretHeaders[http.CanonicalHeaderKey(name)] {
+			value = Redacted
+		}
+		attrs = append(attrs, slog.String(name, value))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// redactBody returns a JSON body with the values of secret fields replaced,
+// truncated to maxLoggedBody bytes. Bodies that are not JSON are summarized
+// by their size since they cannot be redacted.
+func redactBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Sprintf("[%d bytes of non-JSON body]", len(body))
+	}
+	redacted, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return fmt.Sprintf("[%d bytes of body]", len(body))
+	}
+	if len(redacted) > maxLoggedBody {
+		return string(redacted[:maxLoggedBody]) + "...(truncated)"
+	}
+	return string(redacted)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if secretFields[strings.ToLower(key)] && value != nil && value != "" {
+				v[key] = Redacted
+			} else {
+				v[key] = redactValue(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+	}
+	return v
+}
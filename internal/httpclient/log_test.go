@@ -0,0 +1,129 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogger_RedactsSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"protectServeKey":"pskey-secret","ftpPassword":"ftp-secret","forceProtectServe":"ON","nested":[{"token":"child-secret"}]}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := New(Config{BaseURL: server.URL, AuthToken: "bearer-secret", Logger: logger})
+
+	body := map[string]interface{}{
+		"name":      "s3",
+		"secretKey": "origin-secret",
+		"accessKey": "access-secret",
+		"password":  "logtarget-secret",
+		"apiKey":    "",
+	}
+	var out map[string]interface{}
+	if err := client.Post(context.Background(), "/services/svc1/options", body, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out["protectServeKey"] != "pskey-secret" {
+		t.Errorf("expected response to be decoded unredacted, got %v", out)
+	}
+
+	logs := buf.String()
+	for _, secret := range []string{"bearer-secret", "origin-secret", "access-secret", "logtarget-secret", "pskey-secret", "ftp-secret", "child-secret"} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("expected %s to be redacted, got logs:\n%s", secret, logs)
+		}
+	}
+	for _, want := range []string{
+		`"msg":"cachefly api request"`,
+		`"msg":"cachefly api exchange"`,
+		`"method":"POST"`,
+		`"path":"/services/svc1/options"`,
+		`"status":200`,
+		`"Authorization":"[REDACTED]"`,
+		`\"name\":\"s3\"`,
+		`\"forceProtectServe\":\"ON\"`,
+		`\"apiKey\":\"\"`,
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("expected logs to contain %s, got:\n%s", want, logs)
+		}
+	}
+}
+
+func TestLogger_InfoOmitsBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"not found"}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	client := New(Config{BaseURL: server.URL, AuthToken: "t", Logger: logger})
+
+	err := client.Get(context.Background(), "/services/missing", nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "not found" {
+		t.Fatalf("expected APIError with message, got %v", err)
+	}
+
+	logs := buf.String()
+	if strings.Count(logs, "\n") != 1 {
+		t.Errorf("expected a single record, got:\n%s", logs)
+	}
+	if !strings.Contains(logs, "status=404") || !strings.Contains(logs, "path=/services/missing") {
+		t.Errorf("expected status and path in logs, got:\n%s", logs)
+	}
+	if strings.Contains(logs, "not found") {
+		t.Errorf("expected no body at info level, got:\n%s", logs)
+	}
+}
+
+func TestLogger_TransportError(t *testing.T) {
+	var buf bytes.Buffer
+	client := New(Config{
+		BaseURL: "http://cachefly.invalid",
+		Logger:  slog.New(slog.NewTextHandler(&buf, nil)),
+		HTTPClient: &http.Client{Transport: RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		})},
+	})
+
+	if err := client.Get(context.Background(), "/services", nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if logs := buf.String(); !strings.Contains(logs, "level=WARN") || !strings.Contains(logs, "connection refused") {
+		t.Errorf("expected warning with the error, got:\n%s", logs)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"empty", "", ""},
+		{"not json", "password=hunter2", "[16 bytes of non-JSON body]"},
+		{"case insensitive", `{"SecretKey":"s","count":12345678901234567890}`, `{"SecretKey":"[REDACTED]","count":12345678901234567890}`},
+		{"null secret", `{"password":null}`, `{"password":null}`},
+		{"object secret", `{"jsonKey":{"private_key":"k"}}`, `{"jsonKey":"[REDACTED]"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactBody([]byte(tt.body)); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package httpclient
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiting the rate of outgoing API requests.
+//
+// It refills at a fixed rate up to a burst size and additionally pauses all
+// requests when the API reports, through rate limit headers or a 429
+// response, that the quota is exhausted. A single limiter is shared by every
+// service group of a client.
+type RateLimiter struct {
+	mu          sync.Mutex
+	rate        float64 // tokens per second
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	now         func() time.Time
+}
+
+// NewRateLimiter returns a limiter allowing rps requests per second with bursts
+// of up to burst requests. A burst below 1 is treated as 1.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}
+}
+
+// Wait blocks until a request may be sent or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := l.now()
+	l.refill(now)
+	l.tokens--
+
+	var wait time.Duration
+	if l.tokens < 0 && l.rate > 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	if pause := l.pausedUntil.Sub(now); pause > wait {
+		wait = pause
+	}
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	if err := sleep(ctx, wait); err != nil {
+		// give the unused token back
+		l.mu.Lock()
+		l.tokens = math.Min(l.burst, l.tokens+1)
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (l *RateLimiter) refill(now time.Time) {
+	if !l.last.IsZero() {
+		elapsed := now.Sub(l.last).Seconds()
+		l.tokens = math.Min(l.burst, l.tokens+elapsed*l.rate)
+	}
+	l.last = now
+}
+
+// Observe adapts the limiter to the rate limit state reported by a response.
+//
+// When X-RateLimit-Remaining drops to zero, requests are paused until
+// X-RateLimit-Reset. A 429 response pauses requests for its Retry-After
+// delay. Local tokens never exceed the remaining server quota.
+func (l *RateLimiter) Observe(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	h := resp.Header
+
+	if remaining, err := strconv.Atoi(firstHeader(h, "X-RateLimit-Remaining", "RateLimit-Remaining")); err == nil {
+		l.refill(now)
+		if float64(remaining) < l.tokens {
+			l.tokens = float64(remaining)
+		}
+		if remaining <= 0 {
+			if reset := parseRateLimitReset(firstHeader(h, "X-RateLimit-Reset", "RateLimit-Reset"), now); reset > 0 {
+				l.pauseUntil(now.Add(reset))
+			}
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if d := parseRetryAfter(h.Get("Retry-After"), now); d > 0 {
+			l.pauseUntil(now.Add(d))
+		}
+	}
+}
+
+func (l *RateLimiter) pauseUntil(t time.Time) {
+	if t.After(l.pausedUntil) {
+		l.pausedUntil = t
+	}
+}
+
+func firstHeader(h http.Header, names ...string) string {
+	for _, name := range names {
+		if v := h.Get(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// parseRateLimitReset parses a rate limit reset header given either as
+// seconds until reset or as a Unix timestamp.
+func parseRateLimitReset(value string, now time.Time) time.Duration {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n <= 0 {
+		return 0
+	}
+	// values this large can only be Unix timestamps
+	if n > 1_000_000_000 {
+		return time.Unix(n, 0).Sub(now)
+	}
+	return time.Duration(n) * time.Second
+}
@@ -0,0 +1,139 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock returns a controllable time source for the limiter.
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	c.t = c.t.Add(d)
+	c.mu.Unlock()
+}
+
+func TestRateLimiter_Burst(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	l := NewRateLimiter(1, 3)
+	l.now = clock.now
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("request %d within burst should not block: %v", i, err)
+		}
+	}
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected request beyond burst to block until ctx deadline, got %v", err)
+	}
+
+	clock.advance(time.Second)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("expected refilled token after 1s, got %v", err)
+	}
+}
+
+func TestRateLimiter_PausesOnExhaustedQuota(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	l := NewRateLimiter(100, 100)
+	l.now = clock.now
+
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	resp.Header.Set("X-RateLimit-Remaining", "0")
+	resp.Header.Set("X-RateLimit-Reset", "30")
+	l.Observe(resp)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected limiter to pause until reset, got %v", err)
+	}
+
+	clock.advance(31 * time.Second)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("expected limiter to resume after reset, got %v", err)
+	}
+}
+
+func TestRateLimiter_PausesOn429(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	l := NewRateLimiter(100, 100)
+	l.now = clock.now
+
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "5")
+	l.Observe(resp)
+
+	l.mu.Lock()
+	paused := l.pausedUntil.Sub(clock.now())
+	l.mu.Unlock()
+	if paused != 5*time.Second {
+		t.Fatalf("expected 5s pause, got %v", paused)
+	}
+}
+
+func TestRateLimiter_SharedAcrossRequests(t *testing.T) {
+	var mu sync.Mutex
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(100))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := New(Config{BaseURL: server.URL, AuthToken: "t", RateLimiter: NewRateLimiter(1000, 2)})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.Get(ctx, "/services", nil); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 10 {
+		t.Errorf("expected 10 calls, got %d", calls)
+	}
+}
+
+func TestParseRateLimitReset(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	if d := parseRateLimitReset("15", now); d != 15*time.Second {
+		t.Errorf("expected 15s, got %v", d)
+	}
+	if d := parseRateLimitReset("1700000020", now); d != 20*time.Second {
+		t.Errorf("expected 20s from epoch reset, got %v", d)
+	}
+	if d := parseRateLimitReset("", now); d != 0 {
+		t.Errorf("expected 0, got %v", d)
+	}
+}
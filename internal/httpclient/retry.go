@@ -0,0 +1,146 @@
+package httpclient
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures automatic retries of failed API requests.
+//
+// A request is retried when the transport fails or the API responds with one
+// of RetryableStatus. Only idempotent methods (GET, PUT, DELETE) are retried
+// unless RetryNonIdempotent is set.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values of 1 or less disable retries.
+	MaxAttempts int
+
+	// BaseBackoff is the delay before the first retry. It doubles on every
+	// subsequent retry.
+	BaseBackoff time.Duration
+
+	// MaxBackoff caps the computed backoff delay. Zero means no cap.
+	MaxBackoff time.Duration
+
+	// Jitter is the fraction (0 to 1) of each delay that is randomized to
+	// avoid synchronized retries from concurrent clients.
+	Jitter float64
+
+	// RetryNonIdempotent enables retries of POST requests.
+	RetryNonIdempotent bool
+
+	// RetryableStatus lists the HTTP status codes that trigger a retry.
+	// Defaults to 429, 502, 503 and 504 when empty.
+	RetryableStatus []int
+
+	// IgnoreRetryAfter disables honoring the Retry-After response header.
+	IgnoreRetryAfter bool
+
+	// OnRetry, if set, is called before waiting for each retry.
+	OnRetry func(RetryEvent)
+}
+
+// RetryEvent describes a failed attempt that is about to be retried.
+type RetryEvent struct {
+	// Attempt is the 1-based number of the attempt that failed.
+	Attempt int
+	// Method and URL identify the request.
+	Method string
+	URL    string
+	// StatusCode is the response status, or 0 if the transport failed.
+	StatusCode int
+	// Err is the error of the failed attempt.
+	Err error
+	// Delay is how long the client waits before the next attempt.
+	Delay time.Duration
+}
+
+// DefaultRetryPolicy returns a policy suitable for most workloads: up to 4
+// attempts with exponential backoff from 500ms to 30s and 20% jitter.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseBackoff: 500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+var defaultRetryableStatus = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+func (p *RetryPolicy) allowsMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return p.RetryNonIdempotent
+}
+
+func (p *RetryPolicy) retryableStatus(code int) bool {
+	if len(p.RetryableStatus) == 0 {
+		return slices.Contains(defaultRetryableStatus, code)
+	}
+	return slices.Contains(p.RetryableStatus, code)
+}
+
+// backoff returns the delay before the retry following the given attempt.
+func (p *RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	delay := p.BaseBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || delay < p.MaxBackoff); i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if p.Jitter > 0 && delay > 0 {
+		jitter := min(p.Jitter, 1)
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+	}
+	if !p.IgnoreRetryAfter && retryAfter > delay {
+		delay = retryAfter
+	}
+	return delay
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
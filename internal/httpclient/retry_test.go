@@ -0,0 +1,188 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func fastRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+}
+
+func TestRetry_TransientStatusThenSuccess(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"_id":"svc-1"}`))
+	}))
+	defer server.Close()
+
+	var events []RetryEvent
+	policy := fastRetryPolicy()
+	policy.OnRetry = func(e RetryEvent) { events = append(events, e) }
+
+	client := New(Config{BaseURL: server.URL, AuthToken: "t", RetryPolicy: policy})
+
+	var out struct {
+		ID string `json:"_id"`
+	}
+	if err := client.Get(context.Background(), "/services/svc-1", &out); err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if out.ID != "svc-1" {
+		t.Errorf("unexpected body %+v", out)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+	if len(events) != 2 || events[0].Attempt != 1 || events[0].StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("unexpected retry events %+v", events)
+	}
+}
+
+func TestRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := New(Config{BaseURL: server.URL, AuthToken: "t", RetryPolicy: fastRetryPolicy()})
+	err := client.Delete(context.Background(), "/origins/o-1", nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected 502 APIError, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestRetry_PostNotRetriedByDefault(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := New(Config{BaseURL: server.URL, AuthToken: "t", RetryPolicy: fastRetryPolicy()})
+	if err := client.Post(context.Background(), "/services", struct{}{}, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Errorf("expected a single attempt for POST, got %d", calls)
+	}
+
+	calls = 0
+	policy := fastRetryPolicy()
+	policy.RetryNonIdempotent = true
+	client = New(Config{BaseURL: server.URL, AuthToken: "t", RetryPolicy: policy})
+	client.Post(context.Background(), "/services", struct{}{}, nil)
+	if calls != 3 {
+		t.Errorf("expected 3 attempts with RetryNonIdempotent, got %d", calls)
+	}
+}
+
+func TestRetry_NonRetryableStatus(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := New(Config{BaseURL: server.URL, AuthToken: "t", RetryPolicy: fastRetryPolicy()})
+	client.Get(context.Background(), "/services/missing", nil)
+	if calls != 1 {
+		t.Errorf("expected a single attempt for 404, got %d", calls)
+	}
+}
+
+func TestRetry_HonorsRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var delay time.Duration
+	policy := fastRetryPolicy()
+	policy.OnRetry = func(e RetryEvent) { delay = e.Delay }
+
+	client := New(Config{BaseURL: server.URL, AuthToken: "t", RetryPolicy: policy})
+	if err := client.Get(context.Background(), "/services", nil); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if delay != time.Second {
+		t.Errorf("expected Retry-After delay of 1s, got %v", delay)
+	}
+}
+
+func TestRetry_ContextCancelledDuringBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	policy := &RetryPolicy{MaxAttempts: 5, BaseBackoff: time.Hour}
+	client := New(Config{BaseURL: server.URL, AuthToken: "t", RetryPolicy: policy})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := client.Get(ctx, "/services", nil)
+	if !errors.Is(err, ErrServer) {
+		t.Fatalf("expected last API error to be returned, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("backoff did not respect context cancellation")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if d := parseRetryAfter("3", now); d != 3*time.Second {
+		t.Errorf("expected 3s, got %v", d)
+	}
+	if d := parseRetryAfter(now.Add(10*time.Second).Format(http.TimeFormat), now); d != 10*time.Second {
+		t.Errorf("expected 10s, got %v", d)
+	}
+	if d := parseRetryAfter("garbage", now); d != 0 {
+		t.Errorf("expected 0, got %v", d)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for i, w := range want {
+		if got := p.backoff(i+1, 0); got != w {
+			t.Errorf("attempt %d: expected %v, got %v", i+1, w, got)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 50; i++ {
+		if got := p.backoff(1, 0); got < 50*time.Millisecond || got > 100*time.Millisecond {
+			t.Fatalf("jittered delay %v out of range", got)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package httpclient
+
+import "context"
+
+// TokenProvider supplies the API token. It is consulted before every request,
+// so a provider may return a new token at any time to rotate credentials.
+type TokenProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenProviderFunc adapts an ordinary function to TokenProvider.
+type TokenProviderFunc func(ctx context.Context) (string, error)
+
+// Token calls f(ctx).
+func (f TokenProviderFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticToken is a TokenProvider that always returns the same token.
+type StaticToken string
+
+// Token returns t.
+func (t StaticToken) Token(context.Context) (string, error) {
+	return string(t), nil
+}
@@ -0,0 +1,52 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTokenProvider(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Authorization"))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	token := "first"
+	client := New(Config{
+		BaseURL:   server.URL,
+		AuthToken: "ignored",
+		TokenProvider: TokenProviderFunc(func(ctx context.Context) (string, error) {
+			return token, nil
+		}),
+	})
+	client.Get(context.Background(), "/services", nil)
+	token = "second"
+	client.Get(context.Background(), "/services", nil)
+
+	if len(got) != 2 || got[0] != "Bearer first" || got[1] != "Bearer second" {
+		t.Errorf("expected the provider to be consulted per request, got %v", got)
+	}
+}
+
+func TestTokenProvider_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected no request to be sent")
+	}))
+	defer server.Close()
+
+	failure := errors.New("vault unavailable")
+	client := New(Config{
+		BaseURL: server.URL,
+		TokenProvider: TokenProviderFunc(func(ctx context.Context) (string, error) {
+			return "", failure
+		}),
+	})
+	if err := client.Get(context.Background(), "/services", nil); !errors.Is(err, failure) {
+		t.Errorf("expected the provider error, got %v", err)
+	}
+}
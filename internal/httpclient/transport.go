@@ -0,0 +1,60 @@
+package httpclient
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultTimeout is the request timeout used when no http.Client or timeout is configured.
+	DefaultTimeout = 35 * time.Second
+
+	// DefaultUserAgent is sent with every request unless overridden.
+	DefaultUserAgent = "cachefly-sdk-go"
+)
+
+// RoundTripperFunc adapts an ordinary function to http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the transport used for every API request. It can inspect
+// or modify requests before calling next and responses after it returns.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// buildHTTPClient returns the *http.Client used by a Client. The caller's
+// client is copied, never modified, so it can safely be shared.
+func buildHTTPClient(cfg Config) *http.Client {
+	var hc http.Client
+	if cfg.HTTPClient != nil {
+		hc = *cfg.HTTPClient
+	} else {
+		hc.Timeout = DefaultTimeout
+	}
+	if cfg.Timeout > 0 {
+		hc.Timeout = cfg.Timeout
+	}
+
+	if len(cfg.Middleware) > 0 || cfg.Logger != nil {
+		transport := hc.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		// the logger sees requests as sent, after all middleware
+		if cfg.Logger != nil {
+			transport = loggingTransport(cfg.Logger, transport)
+		}
+		// the first middleware is the outermost
+		for i := len(cfg.Middleware) - 1; i >= 0; i-- {
+			if cfg.Middleware[i] != nil {
+				transport = cfg.Middleware[i](transport)
+			}
+		}
+		hc.Transport = transport
+	}
+
+	return &hc
+}
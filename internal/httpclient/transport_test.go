@@ -0,0 +1,86 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMiddleware_Order(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Trace"); got != "outer,inner" {
+			t.Errorf("expected X-Trace outer,inner, got %q", got)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				if v := req.Header.Get("X-Trace"); v != "" {
+					req.Header.Set("X-Trace", v+","+name)
+				} else {
+					req.Header.Set("X-Trace", name)
+				}
+				resp, err := next.RoundTrip(req)
+				order = append(order, name)
+				return resp, err
+			})
+		}
+	}
+
+	client := New(Config{
+		BaseURL:    server.URL,
+		AuthToken:  "t",
+		Middleware: []Middleware{tag("outer"), tag("inner")},
+	})
+	if err := client.Get(context.Background(), "/services", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(order) != 2 || order[0] != "inner" || order[1] != "outer" {
+		t.Errorf("expected responses to unwind inner then outer, got %v", order)
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	New(Config{BaseURL: server.URL}).Get(context.Background(), "/", nil)
+	if got != DefaultUserAgent {
+		t.Errorf("expected default user agent, got %q", got)
+	}
+
+	New(Config{BaseURL: server.URL, UserAgent: "my-tool/1.0"}).Get(context.Background(), "/", nil)
+	if got != "my-tool/1.0" {
+		t.Errorf("expected custom user agent, got %q", got)
+	}
+}
+
+func TestBuildHTTPClient(t *testing.T) {
+	if hc := buildHTTPClient(Config{}); hc.Timeout != DefaultTimeout {
+		t.Errorf("expected default timeout, got %v", hc.Timeout)
+	}
+
+	custom := &http.Client{Timeout: time.Minute}
+	hc := buildHTTPClient(Config{HTTPClient: custom, Timeout: time.Second, Middleware: []Middleware{
+		func(next http.RoundTripper) http.RoundTripper { return next },
+	}})
+	if hc == custom {
+		t.Fatal("expected the caller's client to be copied")
+	}
+	if hc.Timeout != time.Second {
+		t.Errorf("expected timeout override, got %v", hc.Timeout)
+	}
+	if custom.Timeout != time.Minute || custom.Transport != nil {
+		t.Errorf("caller's client was modified")
+	}
+}
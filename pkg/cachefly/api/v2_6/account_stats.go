@@ -2,7 +2,6 @@ package v2_6
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/cachefly/cachefly-sdk-go/internal/httpclient"
 )
@@ -12,87 +11,72 @@ type AccountStatsService struct {
 	Client *httpclient.Client
 }
 
-// internal helper for GET /stats endpoints under account scope
-func (s *AccountStatsService) get(ctx context.Context, endpoint string, opts StatsQueryOptions) (*StatsResponse, error) {
-	params := opts.toURLValues()
-	url := endpoint
-	if len(params) > 0 {
-		url = fmt.Sprintf("%s?%s", endpoint, params.Encode())
-	}
-
-	var out StatsResponse
-	if err := s.Client.Get(ctx, url, &out); err != nil {
-		return nil, err
-	}
-	return &out, nil
-}
-
 // POP returns account POP stats.
 // Docs: https://portal.cachefly.com/api/2.6/docs/#tag/Regular-Account-Stats
-func (s *AccountStatsService) POP(ctx context.Context, opts StatsQueryOptions) (*StatsResponse, error) {
-	if opts.From == "" || opts.To == "" {
-		return nil, fmt.Errorf("'from' and 'to' parameters are required")
+func (s *AccountStatsService) POP(ctx context.Context, opts StatsQueryOptions) (*StatsResult[POPStatsRow], error) {
+	if err := opts.validatePeriod(); err != nil {
+		return nil, err
 	}
-	return s.get(ctx, "/stats/pop", opts)
+	return getStats[POPStatsRow](ctx, s.Client, "/stats/pop", opts)
 }
 
 // Country returns account country stats.
-func (s *AccountStatsService) Country(ctx context.Context, opts StatsQueryOptions) (*StatsResponse, error) {
-	if opts.From == "" || opts.To == "" {
-		return nil, fmt.Errorf("'from' and 'to' parameters are required")
+func (s *AccountStatsService) Country(ctx context.Context, opts StatsQueryOptions) (*StatsResult[CountryStatsRow], error) {
+	if err := opts.validatePeriod(); err != nil {
+		return nil, err
 	}
-	return s.get(ctx, "/stats/country", opts)
+	return getStats[CountryStatsRow](ctx, s.Client, "/stats/country", opts)
 }
 
 // Cache returns account cache stats.
-func (s *AccountStatsService) Cache(ctx context.Context, opts StatsQueryOptions) (*StatsResponse, error) {
-	if opts.From == "" || opts.To == "" {
-		return nil, fmt.Errorf("'from' and 'to' parameters are required")
+func (s *AccountStatsService) Cache(ctx context.Context, opts StatsQueryOptions) (*StatsResult[CacheStatsRow], error) {
+	if err := opts.validatePeriod(); err != nil {
+		return nil, err
 	}
-	return s.get(ctx, "/stats/cache", opts)
+	return getStats[CacheStatsRow](ctx, s.Client, "/stats/cache", opts)
 }
 
 // Status returns account status stats.
-func (s *AccountStatsService) Status(ctx context.Context, opts StatsQueryOptions) (*StatsResponse, error) {
-	if opts.From == "" || opts.To == "" {
-		return nil, fmt.Errorf("'from' and 'to' parameters are required")
+func (s *AccountStatsService) Status(ctx context.Context, opts StatsQueryOptions) (*StatsResult[StatusStatsRow], error) {
+	if err := opts.validatePeriod(); err != nil {
+		return nil, err
 	}
-	return s.get(ctx, "/stats/status", opts)
+	return getStats[StatusStatsRow](ctx, s.Client, "/stats/status", opts)
 }
 
 // Origin returns account origin stats.
-func (s *AccountStatsService) Origin(ctx context.Context, opts StatsQueryOptions) (*StatsResponse, error) {
-	if opts.From == "" || opts.To == "" {
-		return nil, fmt.Errorf("'from' and 'to' parameters are required")
+func (s *AccountStatsService) Origin(ctx context.Context, opts StatsQueryOptions) (*StatsResult[OriginStatsRow], error) {
+	if err := opts.validatePeriod(); err != nil {
+		return nil, err
 	}
-	return s.get(ctx, "/stats/origin", opts)
+	return getStats[OriginStatsRow](ctx, s.Client, "/stats/origin", opts)
 }
 
 // Storage returns account storage stats.
-func (s *AccountStatsService) Storage(ctx context.Context, opts StatsQueryOptions) (*StatsResponse, error) {
-	if opts.From == "" || opts.To == "" {
-		return nil, fmt.Errorf("'from' and 'to' parameters are required")
+func (s *AccountStatsService) Storage(ctx context.Context, opts StatsQueryOptions) (*StatsResult[StorageStatsRow], error) {
+	if err := opts.validatePeriod(); err != nil {
+		return nil, err
 	}
-	return s.get(ctx, "/stats/storage", opts)
+	return getStats[StorageStatsRow](ctx, s.Client, "/stats/storage", opts)
 }
 
 // Realtime returns account realtime stats.
-func (s *AccountStatsService) Realtime(ctx context.Context, opts StatsQueryOptions) (*StatsResponse, error) {
-	return s.get(ctx, "/stats/realtime", opts)
+func (s *AccountStatsService) Realtime(ctx context.Context, opts StatsQueryOptions) (*StatsResult[RealtimeStatsRow], error) {
+	return getStats[RealtimeStatsRow](ctx, s.Client, "/stats/realtime", opts)
 }
 
 // Path returns account path stats.
-func (s *AccountStatsService) Path(ctx context.Context, opts StatsQueryOptions) (*StatsResponse, error) {
-	if opts.From == "" || opts.To == "" {
-		return nil, fmt.Errorf("'from' and 'to' parameters are required")
+func (s *AccountStatsService) Path(ctx context.Context, opts StatsQueryOptions) (*StatsResult[PathStatsRow], error) {
+	if err := opts.validatePeriod(); err != nil {
+		return nil, err
 	}
-	return s.get(ctx, "/stats/path", opts)
+	return getStats[PathStatsRow](ctx, s.Client, "/stats/path", opts)
 }
 
 // Referer returns account referer stats.
-func (s *AccountStatsService) Referer(ctx context.Context, opts StatsQueryOptions) (*StatsResponse, error) {
-	if opts.From == "" || opts.To == "" {
-		return nil, fmt.Errorf("'from' and 'to' parameters are required")
+func (s *AccountStatsService) Referer(ctx context.Context, opts StatsQueryOptions) (*StatsResult[RefererStatsRow], error) {
+	if err := opts.validatePeriod(); err != nil {
+		return nil, err
 	}
-	return s.get(ctx, "/stats/referer", opts)
+	return getStats[RefererStatsRow](ctx, s.Client, "/stats/referer", opts)
 }
@@ -3,8 +3,10 @@ package v2_6
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/cachefly/cachefly-sdk-go/internal/httpclient"
 )
@@ -136,6 +138,12 @@ type ChildAccountAuthResponse struct {
 	ExpiresAt string `json:"expiresAt"`
 }
 
+// Expiry returns the parsed ExpiresAt time, or the zero time if it is unset
+// or cannot be parsed.
+func (r ChildAccountAuthResponse) Expiry() time.Time {
+	return parseTaskTime(r.ExpiresAt)
+}
+
 // Get retrieves the current authenticated account.
 func (a *AccountsService) Get(ctx context.Context, responseType string) (*Account, error) {
 	endpoint := "/accounts/me"
@@ -188,6 +196,18 @@ func (a *AccountsService) List(ctx context.Context, opts ListAccountsOptions) (*
 	return &result, nil
 }
 
+// All iterates over all accounts, fetching opts.Limit items per page.
+func (a *AccountsService) All(ctx context.Context, opts ListAccountsOptions) iter.Seq2[Account, error] {
+	return paginate(ctx, opts.Offset, opts.Limit, func(ctx context.Context, offset, limit int) ([]Account, MetaInfo, error) {
+		opts.Offset, opts.Limit = offset, limit
+		resp, err := a.List(ctx, opts)
+		if err != nil {
+			return nil, MetaInfo{}, err
+		}
+		return resp.Accounts, resp.Meta, nil
+	})
+}
+
 // GetByID retrieves an account by its ID.
 func (a *AccountsService) GetByID(ctx context.Context, id string, responseType string) (*Account, error) {
 	if id == "" {
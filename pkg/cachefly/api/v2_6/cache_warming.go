@@ -3,8 +3,11 @@ package v2_6
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cachefly/cachefly-sdk-go/internal/httpclient"
 )
@@ -27,6 +30,57 @@ type CacheWarmingTask struct {
 	UpdatedAt        string      `json:"updatedAt,omitempty"`
 }
 
+// CacheWarmingStatus is the state of a cache warming task.
+type CacheWarmingStatus string
+
+const (
+	CacheWarmingPending   CacheWarmingStatus = "PENDING"
+	CacheWarmingQueued    CacheWarmingStatus = "QUEUED"
+	CacheWarmingRunning   CacheWarmingStatus = "RUNNING"
+	CacheWarmingCompleted CacheWarmingStatus = "COMPLETED"
+	CacheWarmingFailed    CacheWarmingStatus = "FAILED"
+	CacheWarmingCancelled CacheWarmingStatus = "CANCELLED"
+)
+
+// Done reports whether the task has stopped, successfully or not. Statuses
+// other than the known ones count as done, so that waiting for them ends.
+func (s CacheWarmingStatus) Done() bool {
+	return s != CacheWarmingPending && s != CacheWarmingQueued && s != CacheWarmingRunning
+}
+
+// Known reports whether s is one of the CacheWarming status constants.
+func (s CacheWarmingStatus) Known() bool {
+	switch s {
+	case CacheWarmingPending, CacheWarmingQueued, CacheWarmingRunning,
+		CacheWarmingCompleted, CacheWarmingFailed, CacheWarmingCancelled:
+		return true
+	}
+	return false
+}
+
+// State returns the status of the task.
+func (t CacheWarmingTask) State() CacheWarmingStatus {
+	return CacheWarmingStatus(strings.ToUpper(t.Status))
+}
+
+// Started returns when the task started, or the zero time if it has not.
+func (t CacheWarmingTask) Started() time.Time {
+	return parseTaskTime(t.StartedAt)
+}
+
+// Stopped returns when the task stopped, or the zero time if it has not.
+func (t CacheWarmingTask) Stopped() time.Time {
+	return parseTaskTime(t.StoppedAt)
+}
+
+func parseTaskTime(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	t, _ := parseStatsTime(s)
+	return t
+}
+
 // CreateCacheWarmingTaskRequest is the payload for creating a task.
 type CreateCacheWarmingTaskRequest struct {
 	Name             string   `json:"name,omitempty"`
@@ -77,6 +131,18 @@ func (s *CacheWarmingService) List(ctx context.Context, opts ListCacheWarmingTas
 	return &resp, nil
 }
 
+// All iterates over all cache warming tasks, fetching opts.Limit items per page.
+func (s *CacheWarmingService) All(ctx context.Context, opts ListCacheWarmingTasksOptions) iter.Seq2[CacheWarmingTask, error] {
+	return paginate(ctx, opts.Offset, opts.Limit, func(ctx context.Context, offset, limit int) ([]CacheWarmingTask, MetaInfo, error) {
+		opts.Offset, opts.Limit = offset, limit
+		resp, err := s.List(ctx, opts)
+		if err != nil {
+			return nil, MetaInfo{}, err
+		}
+		return resp.Data, resp.Meta, nil
+	})
+}
+
 // Create creates a new cache warming task.
 func (s *CacheWarmingService) Create(ctx context.Context, req CreateCacheWarmingTaskRequest) (*CacheWarmingTask, error) {
 	endpoint := "/cachewarming"
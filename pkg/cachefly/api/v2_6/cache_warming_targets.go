@@ -0,0 +1,230 @@
+package v2_6
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+)
+
+// DefaultWarmingTargetsPerTask is the number of targets BuildCacheWarmingTasks
+// puts in each task when CacheWarmingTaskOptions.TargetsPerTask is not set.
+// It is a conservative default, not a documented API limit; set
+// TargetsPerTask to use larger or smaller tasks.
+const DefaultWarmingTargetsPerTask = 500
+
+// SitemapTargets returns the page URLs of a sitemap, in document order and
+// without duplicates. Gzip-compressed sitemaps are decompressed. A sitemap
+// index is rejected with an error listing its sitemaps, which must be read
+// one by one.
+func SitemapTargets(r io.Reader) ([]string, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress sitemap: %w", err)
+		}
+		defer zr.Close()
+		r = zr
+	} else {
+		r = br
+	}
+
+	var doc struct {
+		XMLName  xml.Name
+		URLs     []string `xml:"url>loc"`
+		Sitemaps []string `xml:"sitemap>loc"`
+	}
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse sitemap: %w", err)
+	}
+	switch doc.XMLName.Local {
+	case "urlset":
+		return WarmingTargets(doc.URLs)
+	case "sitemapindex":
+		return nil, fmt.Errorf("sitemap is an index of %d sitemaps, read them instead: %s", len(doc.Sitemaps), strings.Join(doc.Sitemaps, ", "))
+	default:
+		return nil, fmt.Errorf("unexpected sitemap root element <%s>", doc.XMLName.Local)
+	}
+}
+
+// SitemapFileTargets returns the page URLs of a sitemap file. See SitemapTargets.
+func SitemapFileTargets(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return SitemapTargets(f)
+}
+
+// WarmingTargets validates a list of URLs for cache warming: surrounding
+// space is trimmed, blank entries are skipped, duplicates and fragments are
+// dropped, and every URL must be an absolute http or https URL.
+func WarmingTargets(urls []string) ([]string, error) {
+	targets := make([]string, 0, len(urls))
+	seen := map[string]bool{}
+	for _, raw := range urls {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL %q: %w", raw, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid URL %q: expected an absolute http or https URL", raw)
+		}
+		u.Fragment = ""
+		if target := u.String(); !seen[target] {
+			seen[target] = true
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
+
+// TopPathTargets returns the URLs of the n most requested paths of a service
+// in the period of opts, joined to baseURL, e.g. "https://cdn.example.com".
+// Rows are ranked by requests on the client, so opts.Limit must be large
+// enough to include the busiest paths.
+func (s *ServiceStatsService) TopPathTargets(ctx context.Context, sid, baseURL string, n int, opts StatsQueryOptions) ([]string, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: expected an absolute http or https URL", baseURL)
+	}
+	if n <= 0 {
+		return nil, fmt.Errorf("n must be positive")
+	}
+
+	res, err := s.Path(ctx, sid, opts)
+	if err != nil {
+		return nil, err
+	}
+	rows := slices.Clone(res.Data)
+	slices.SortStableFunc(rows, func(a, b PathStatsRow) int {
+		switch {
+		case a.Requests > b.Requests:
+			return -1
+		case a.Requests < b.Requests:
+			return 1
+		}
+		return 0
+	})
+
+	prefix := strings.TrimSuffix(base.String(), "/")
+	var urls []string
+	for _, row := range rows {
+		if row.Path == "" {
+			continue
+		}
+		path := row.Path
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		urls = append(urls, prefix+path)
+	}
+	targets, err := WarmingTargets(urls)
+	if err != nil {
+		return nil, err
+	}
+	return targets[:min(n, len(targets))], nil
+}
+
+// CacheWarmingTaskOptions configures how targets are split into tasks.
+type CacheWarmingTaskOptions struct {
+	// Name is the task name; tasks of a split list are numbered "Name (i/n)".
+	Name string
+	// Regions to warm. CreateTasks warms every delivery region, by ID, when
+	// empty.
+	Regions []string
+	// PerRegion creates separate tasks for each region instead of tasks
+	// warming all regions at once.
+	PerRegion bool
+	// TargetsPerTask is the maximum number of targets of a task.
+	TargetsPerTask int
+
+	ContentTypes     []string
+	ContentEncodings []string
+	ContentLanguages []string
+}
+
+// BuildCacheWarmingTasks splits targets into task requests of at most
+// opts.TargetsPerTask targets, for all of opts.Regions or, with PerRegion, for
+// each region in turn.
+func BuildCacheWarmingTasks(targets []string, opts CacheWarmingTaskOptions) ([]CreateCacheWarmingTaskRequest, error) {
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("at least one target is required")
+	}
+	if len(opts.Regions) == 0 {
+		return nil, fmt.Errorf("at least one region is required")
+	}
+	if opts.TargetsPerTask <= 0 {
+		opts.TargetsPerTask = DefaultWarmingTargetsPerTask
+	}
+
+	regionSets := [][]string{opts.Regions}
+	if opts.PerRegion {
+		regionSets = nil
+		for _, r := range opts.Regions {
+			regionSets = append(regionSets, []string{r})
+		}
+	}
+
+	var reqs []CreateCacheWarmingTaskRequest
+	for _, regions := range regionSets {
+		for chunk := range slices.Chunk(targets, opts.TargetsPerTask) {
+			reqs = append(reqs, CreateCacheWarmingTaskRequest{
+				Targets:          chunk,
+				Regions:          regions,
+				ContentTypes:     opts.ContentTypes,
+				ContentEncodings: opts.ContentEncodings,
+				ContentLanguages: opts.ContentLanguages,
+			})
+		}
+	}
+	for i := range reqs {
+		reqs[i].Name = opts.Name
+		if opts.Name != "" && len(reqs) > 1 {
+			reqs[i].Name = fmt.Sprintf("%s (%d/%d)", opts.Name, i+1, len(reqs))
+		}
+	}
+	return reqs, nil
+}
+
+// CreateTasks creates the tasks built by BuildCacheWarmingTasks, warming
+// every delivery region when opts.Regions is empty. On error it returns the
+// tasks created so far.
+func (s *CacheWarmingService) CreateTasks(ctx context.Context, targets []string, opts CacheWarmingTaskOptions) ([]CacheWarmingTask, error) {
+	if len(opts.Regions) == 0 {
+		regions, err := ListAll((&DeliveryRegionsService{Client: s.Client}).All(ctx, ListDeliveryRegionsOptions{}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list delivery regions: %w", err)
+		}
+		for _, r := range regions {
+			opts.Regions = append(opts.Regions, r.ID)
+		}
+	}
+
+	reqs, err := BuildCacheWarmingTasks(targets, opts)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []CacheWarmingTask
+	for i, req := range reqs {
+		task, err := s.Create(ctx, req)
+		if err != nil {
+			return tasks, fmt.Errorf("failed to create task %d of %d: %w", i+1, len(reqs), err)
+		}
+		tasks = append(tasks, *task)
+	}
+	return tasks, nil
+}
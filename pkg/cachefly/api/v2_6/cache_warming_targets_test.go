@@ -0,0 +1,129 @@
+package v2_6
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cachefly/cachefly-sdk-go/internal/httpclient"
+)
+
+const testSitemap = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://www.example.com/</loc><priority>1.0</priority></url>
+  <url><loc> https://www.example.com/about#team </loc></url>
+  <url><loc>https://www.example.com/</loc></url>
+</urlset>`
+
+func TestSitemapTargets(t *testing.T) {
+	want := []string{"https://www.example.com/", "https://www.example.com/about"}
+
+	targets, err := SitemapTargets(strings.NewReader(testSitemap))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !slices.Equal(targets, want) {
+		t.Errorf("Expected %v, got %v", want, targets)
+	}
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(testSitemap))
+	zw.Close()
+	targets, err = SitemapTargets(&gz)
+	if err != nil || !slices.Equal(targets, want) {
+		t.Errorf("Expected %v from a gzipped sitemap, got %v, %v", want, targets, err)
+	}
+
+	index := `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><sitemap><loc>https://www.example.com/a.xml</loc></sitemap></sitemapindex>`
+	if _, err := SitemapTargets(strings.NewReader(index)); err == nil || !strings.Contains(err.Error(), "https://www.example.com/a.xml") {
+		t.Errorf("Expected an error listing the sitemaps of an index, got %v", err)
+	}
+	if _, err := SitemapTargets(strings.NewReader(`<urlset><url><loc>/relative</loc></url></urlset>`)); err == nil {
+		t.Error("Expected an error for a relative URL")
+	}
+}
+
+func TestBuildCacheWarmingTasks(t *testing.T) {
+	targets := []string{"https://a/1", "https://a/2", "https://a/3"}
+
+	reqs, err := BuildCacheWarmingTasks(targets, CacheWarmingTaskOptions{Name: "warm", Regions: []string{"eu", "us"}, TargetsPerTask: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(reqs) != 2 || len(reqs[0].Targets) != 2 || len(reqs[1].Targets) != 1 || len(reqs[1].Regions) != 2 {
+		t.Errorf("Unexpected tasks %+v", reqs)
+	}
+	if reqs[0].Name != "warm (1/2)" || reqs[1].Name != "warm (2/2)" {
+		t.Errorf("Unexpected names %q, %q", reqs[0].Name, reqs[1].Name)
+	}
+
+	reqs, _ = BuildCacheWarmingTasks(targets, CacheWarmingTaskOptions{Name: "warm", Regions: []string{"eu", "us"}, PerRegion: true})
+	if len(reqs) != 2 || !slices.Equal(reqs[0].Regions, []string{"eu"}) || !slices.Equal(reqs[1].Regions, []string{"us"}) || len(reqs[1].Targets) != 3 {
+		t.Errorf("Expected one task per region, got %+v", reqs)
+	}
+
+	if _, err := BuildCacheWarmingTasks(targets, CacheWarmingTaskOptions{}); err == nil {
+		t.Error("Expected an error without regions")
+	}
+}
+
+func TestServiceStatsService_TopPathTargets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/2.6/services/svc-1/stats/path" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[{"path":"/b.css","requests":5},{"path":"/a.js","requests":50},{"path":"img/c.png","requests":20}]}`))
+	}))
+	defer server.Close()
+
+	svc := &ServiceStatsService{Client: httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "t"})}
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	targets, err := svc.TopPathTargets(context.Background(), "svc-1", "https://cdn.example.com/", 2, StatsQueryOptions{From: from, To: from.AddDate(0, 0, 7)})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := []string{"https://cdn.example.com/a.js", "https://cdn.example.com/img/c.png"}
+	if !slices.Equal(targets, want) {
+		t.Errorf("Expected %v, got %v", want, targets)
+	}
+}
+
+func TestCacheWarmingService_CreateTasks(t *testing.T) {
+	var created []CreateCacheWarmingTaskRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/2.6/deliveryregions":
+			w.Write([]byte(`{"meta":{"count":2},"data":[{"_id":"region-1","name":"Europe"},{"_id":"region-2","name":"North America"}]}`))
+		case "/api/2.6/cachewarming":
+			var req CreateCacheWarmingTaskRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			created = append(created, req)
+			w.Write([]byte(`{"_id":"cw1","status":"PENDING"}`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	svc := &CacheWarmingService{Client: httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "t"})}
+	tasks, err := svc.CreateTasks(context.Background(), []string{"https://a/1", "https://a/2"}, CacheWarmingTaskOptions{TargetsPerTask: 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(tasks) != 2 || len(created) != 2 {
+		t.Fatalf("Expected two tasks, got %d", len(created))
+	}
+	if !slices.Equal(created[0].Regions, []string{"region-1", "region-2"}) {
+		t.Errorf("Expected every delivery region, got %v", created[0].Regions)
+	}
+}
@@ -2,9 +2,13 @@ package v2_6
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cachefly/cachefly-sdk-go/internal/httpclient"
 )
@@ -102,3 +106,76 @@ func TestCacheWarming_Validation(t *testing.T) {
 		t.Fatalf("expected error for missing id on delete")
 	}
 }
+
+// warmingStatusServer serves task cw1 with the given statuses, one per poll,
+// repeating the last one.
+func warmingStatusServer(t *testing.T, statuses ...string) *CacheWarmingService {
+	t.Helper()
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[min(polls, len(statuses)-1)]
+		polls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"_id":"cw1","status":"` + status + `","startedAt":"2025-01-02T03:04:05Z"}`))
+	}))
+	t.Cleanup(server.Close)
+	return &CacheWarmingService{Client: httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "t"})}
+}
+
+func TestCacheWarming_Watch(t *testing.T) {
+	svc := warmingStatusServer(t, "PENDING", "PENDING", "RUNNING", "RUNNING", "COMPLETED")
+
+	var seen []CacheWarmingStatus
+	for task, err := range svc.Watch(context.Background(), "cw1", time.Millisecond) {
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		seen = append(seen, task.State())
+	}
+	want := []CacheWarmingStatus{CacheWarmingPending, CacheWarmingRunning, CacheWarmingCompleted}
+	if !slices.Equal(seen, want) {
+		t.Errorf("Expected transitions %v, got %v", want, seen)
+	}
+}
+
+func TestCacheWarming_WaitForCompletion(t *testing.T) {
+	task, err := warmingStatusServer(t, "running", "completed").WaitForCompletion(context.Background(), "cw1", time.Millisecond)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if task.State() != CacheWarmingCompleted {
+		t.Errorf("Expected COMPLETED, got %s", task.Status)
+	}
+	if want := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC); !task.Started().Equal(want) || !task.Stopped().IsZero() {
+		t.Errorf("Unexpected times started=%v stopped=%v", task.Started(), task.Stopped())
+	}
+
+	_, err = warmingStatusServer(t, "RUNNING", "FAILED").WaitForCompletion(context.Background(), "cw1", time.Millisecond)
+	var warmErr *CacheWarmingError
+	if !errors.As(err, &warmErr) || warmErr.Task.State() != CacheWarmingFailed {
+		t.Errorf("Expected *CacheWarmingError for a failed task, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	task, err = warmingStatusServer(t, "RUNNING").WaitForCompletion(ctx, "cw1", time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if task == nil || task.State() != CacheWarmingRunning {
+		t.Errorf("Expected the last known state, got %+v", task)
+	}
+}
+
+func TestCacheWarming_WaitForCompletion_UnknownStatus(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	task, err := warmingStatusServer(t, "QUEUED", "RUNNING", "ARCHIVED").WaitForCompletion(ctx, "cw1", time.Millisecond)
+	if !errors.Is(err, ErrUnknownCacheWarmingStatus) || !strings.Contains(err.Error(), `"ARCHIVED"`) {
+		t.Errorf("Expected an unknown status error, got %v", err)
+	}
+	if task == nil || task.Status != "ARCHIVED" {
+		t.Errorf("Expected the task with the unknown status, got %+v", task)
+	}
+}
@@ -0,0 +1,100 @@
+package v2_6
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+	"time"
+)
+
+// DefaultCacheWarmingPollInterval is the poll interval used by Watch and
+// WaitForCompletion when none is given.
+const DefaultCacheWarmingPollInterval = 5 * time.Second
+
+// ErrUnknownCacheWarmingStatus is returned by Watch and WaitForCompletion when
+// a task reports a status other than the CacheWarming constants.
+var ErrUnknownCacheWarmingStatus = errors.New("unknown cache warming task status")
+
+// CacheWarmingError is returned when a task stops without completing.
+type CacheWarmingError struct {
+	Task CacheWarmingTask
+}
+
+func (e *CacheWarmingError) Error() string {
+	return fmt.Sprintf("cache warming task %s %s", e.Task.ID, strings.ToLower(e.Task.Status))
+}
+
+// Watch polls a task every pollInterval and yields it each time its status
+// changes, starting with its current state. The sequence ends once the task
+// is done. A task that fails or is cancelled is yielded with a
+// *CacheWarmingError, and one with an unknown status with an error wrapping
+// ErrUnknownCacheWarmingStatus. Request errors and the cancellation of ctx end the
+// sequence too; they are yielded with the last state seen, if any.
+func (s *CacheWarmingService) Watch(ctx context.Context, id string, pollInterval time.Duration) iter.Seq2[CacheWarmingTask, error] {
+	if pollInterval <= 0 {
+		pollInterval = DefaultCacheWarmingPollInterval
+	}
+	return func(yield func(CacheWarmingTask, error) bool) {
+		var last CacheWarmingTask
+		for first := true; ; first = false {
+			task, err := s.GetByID(ctx, id)
+			if err != nil {
+				if ctx.Err() != nil {
+					err = fmt.Errorf("waiting for cache warming task %s: %w", id, ctx.Err())
+				}
+				yield(last, err)
+				return
+			}
+
+			changed := first || task.Status != last.Status
+			last = *task
+			if changed {
+				var taskErr error
+				switch {
+				case !task.State().Known():
+					taskErr = fmt.Errorf("%w %q of task %s", ErrUnknownCacheWarmingStatus, task.Status, task.ID)
+				case task.State().Done() && task.State() != CacheWarmingCompleted:
+					taskErr = &CacheWarmingError{Task: *task}
+				}
+				if !yield(*task, taskErr) {
+					return
+				}
+			}
+			if task.State().Done() {
+				return
+			}
+
+			timer := time.NewTimer(pollInterval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				yield(last, fmt.Errorf("waiting for cache warming task %s: %w", id, ctx.Err()))
+				return
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+// WaitForCompletion polls a task until it is done and returns its final state.
+// It returns a *CacheWarmingError if the task fails or is cancelled, an error
+// wrapping ErrUnknownCacheWarmingStatus for a status it does not know, and an
+// error wrapping ctx.Err() if ctx is done first.
+func (s *CacheWarmingService) WaitForCompletion(ctx context.Context, id string, pollInterval time.Duration) (*CacheWarmingTask, error) {
+	if id == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+	var last CacheWarmingTask
+	for task, err := range s.Watch(ctx, id, pollInterval) {
+		if err != nil {
+			if task.ID == "" {
+				return nil, err
+			}
+			return &task, err
+		}
+		last = task
+	}
+	return &last, nil
+}
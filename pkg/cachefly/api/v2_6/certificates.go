@@ -3,6 +3,7 @@ package v2_6
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 
@@ -78,6 +79,18 @@ func (s *CertificatesService) List(ctx context.Context, opts ListCertificatesOpt
 	return &resp, nil
 }
 
+// All iterates over all certificates, fetching opts.Limit items per page.
+func (s *CertificatesService) All(ctx context.Context, opts ListCertificatesOptions) iter.Seq2[Certificate, error] {
+	return paginate(ctx, opts.Offset, opts.Limit, func(ctx context.Context, offset, limit int) ([]Certificate, MetaInfo, error) {
+		opts.Offset, opts.Limit = offset, limit
+		resp, err := s.List(ctx, opts)
+		if err != nil {
+			return nil, MetaInfo{}, err
+		}
+		return resp.Certificates, resp.Meta, nil
+	})
+}
+
 // Create uploads a new TLS/SSL certificate.
 func (s *CertificatesService) Create(ctx context.Context, req CreateCertificateRequest) (*Certificate, error) {
 	if req.Certificate == "" || req.CertificateKey == "" {
@@ -0,0 +1,234 @@
+package v2_6
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Errors reported by CertificateBundle.Check, wrapped with details. Use
+// errors.Is to test for them.
+var (
+	ErrCertificateKeyMismatch  = errors.New("private key does not match the certificate")
+	ErrCertificateChainOrder   = errors.New("certificate chain is out of order")
+	ErrCertificateNameMismatch = errors.New("certificate does not cover the domain")
+	ErrCertificateExpired      = errors.New("certificate is expired")
+	ErrCertificateNotYetValid  = errors.New("certificate is not valid yet")
+)
+
+// CertificateBundle is a certificate with its intermediate chain and private
+// key, parsed locally so that it can be checked before upload.
+type CertificateBundle struct {
+	Leaf *x509.Certificate
+	// Chain holds the intermediates, starting with the issuer of Leaf.
+	Chain []*x509.Certificate
+	Key   crypto.Signer
+}
+
+// CertificateCheckOptions configures CertificateBundle.Check.
+type CertificateCheckOptions struct {
+	// Domains the certificate must be valid for; wildcard domains such as
+	// "*.example.com" must be listed in the certificate as is.
+	Domains []string
+	// MinValidity rejects certificates expiring sooner than this.
+	MinValidity time.Duration
+	// Now is the time to check validity at, defaulting to the current time.
+	Now time.Time
+}
+
+// NewCertificateBundle returns a bundle of already parsed certificates and key.
+func NewCertificateBundle(leaf *x509.Certificate, chain []*x509.Certificate, key crypto.Signer) (*CertificateBundle, error) {
+	if leaf == nil {
+		return nil, fmt.Errorf("certificate is required")
+	}
+	if key == nil {
+		return nil, fmt.Errorf("private key is required")
+	}
+	return &CertificateBundle{Leaf: leaf, Chain: chain, Key: key}, nil
+}
+
+// ParseCertificateBundle parses PEM-encoded certificates and a private key.
+// The first certificate of certPEM is the leaf; any following ones and those
+// of chainPEM make up the chain. PKCS #1, PKCS #8 and SEC 1 keys are
+// supported; encrypted keys are not.
+func ParseCertificateBundle(certPEM, keyPEM []byte, chainPEM ...[]byte) (*CertificateBundle, error) {
+	certs, err := parseCertificates(bytes.Join(append([][]byte{certPEM}, chainPEM...), []byte("\n")))
+	if err != nil {
+		return nil, err
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificate found in PEM data")
+	}
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return NewCertificateBundle(certs[0], certs[1:], key)
+}
+
+// LoadCertificateBundle reads a bundle from PEM files. See ParseCertificateBundle.
+func LoadCertificateBundle(certFile, keyFile string, chainFiles ...string) (*CertificateBundle, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	chain := make([][]byte, len(chainFiles))
+	for i, f := range chainFiles {
+		if chain[i], err = os.ReadFile(f); err != nil {
+			return nil, err
+		}
+	}
+	return ParseCertificateBundle(certPEM, keyPEM, chain...)
+}
+
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block %q in certificate data", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate %d: %w", len(certs)+1, err)
+		}
+		certs = append(certs, cert)
+	}
+}
+
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no private key found in PEM data")
+	}
+	if _, encrypted := block.Headers["Proc-Type"]; encrypted || block.Type == "ENCRYPTED PRIVATE KEY" {
+		return nil, fmt.Errorf("encrypted private keys are not supported, decrypt the key first")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unexpected PEM block %q in private key data", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+// Check verifies that the key matches the certificate, that each chain
+// certificate issued the one before it, that the certificate covers
+// opts.Domains and that it is valid now and for opts.MinValidity. All
+// problems found are returned together.
+func (b *CertificateBundle) Check(opts CertificateCheckOptions) error {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	var errs []error
+
+	type publicKey interface{ Equal(crypto.PublicKey) bool }
+	if pub, ok := b.Key.Public().(publicKey); !ok || !pub.Equal(b.Leaf.PublicKey) {
+		errs = append(errs, ErrCertificateKeyMismatch)
+	}
+
+	certs := append([]*x509.Certificate{b.Leaf}, b.Chain...)
+	for i := 1; i < len(certs); i++ {
+		if err := certs[i-1].CheckSignatureFrom(certs[i]); err != nil {
+			errs = append(errs, fmt.Errorf("%w: %q was not issued by the next certificate %q", ErrCertificateChainOrder, certName(certs[i-1]), certName(certs[i])))
+		}
+	}
+
+	for _, domain := range opts.Domains {
+		if !certificateCovers(b.Leaf, domain) {
+			errs = append(errs, fmt.Errorf("%w %s, it is valid for %s", ErrCertificateNameMismatch, domain, strings.Join(b.Leaf.DNSNames, ", ")))
+		}
+	}
+
+	switch {
+	case now.Before(b.Leaf.NotBefore):
+		errs = append(errs, fmt.Errorf("%w: valid from %s", ErrCertificateNotYetValid, b.Leaf.NotBefore.Format(time.RFC3339)))
+	case now.After(b.Leaf.NotAfter):
+		errs = append(errs, fmt.Errorf("%w: expired at %s", ErrCertificateExpired, b.Leaf.NotAfter.Format(time.RFC3339)))
+	case now.Add(opts.MinValidity).After(b.Leaf.NotAfter):
+		errs = append(errs, fmt.Errorf("%w: expires at %s, within %v", ErrCertificateExpired, b.Leaf.NotAfter.Format(time.RFC3339), opts.MinValidity))
+	}
+	return errors.Join(errs...)
+}
+
+func certName(c *x509.Certificate) string {
+	if c.Subject.CommonName != "" {
+		return c.Subject.CommonName
+	}
+	return c.Subject.String()
+}
+
+// certificateCovers reports whether cert is valid for domain. Wildcard
+// domains must be among the certificate's names.
+func certificateCovers(cert *x509.Certificate, domain string) bool {
+	if strings.HasPrefix(domain, "*.") {
+		for _, name := range cert.DNSNames {
+			if strings.EqualFold(name, domain) {
+				return true
+			}
+		}
+		return false
+	}
+	return cert.VerifyHostname(domain) == nil
+}
+
+// Request encodes the bundle as a CreateCertificateRequest: the certificate
+// followed by its chain, and the key in PKCS #8 form.
+func (b *CertificateBundle) Request() (CreateCertificateRequest, error) {
+	var certs bytes.Buffer
+	for _, c := range append([]*x509.Certificate{b.Leaf}, b.Chain...) {
+		pem.Encode(&certs, &pem.Block{Type: "CERTIFICATE", Bytes: c.Raw})
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(b.Key)
+	if err != nil {
+		return CreateCertificateRequest{}, fmt.Errorf("failed to encode private key: %w", err)
+	}
+	return CreateCertificateRequest{
+		Certificate:    certs.String(),
+		CertificateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+	}, nil
+}
+
+// Upload checks a bundle with opts and uploads it. Nothing is sent when the
+// check fails.
+func (s *CertificatesService) Upload(ctx context.Context, bundle *CertificateBundle, opts CertificateCheckOptions) (*Certificate, error) {
+	if err := bundle.Check(opts); err != nil {
+		return nil, err
+	}
+	req, err := bundle.Request()
+	if err != nil {
+		return nil, err
+	}
+	return s.Create(ctx, req)
+}
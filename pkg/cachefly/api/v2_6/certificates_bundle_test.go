@@ -0,0 +1,159 @@
+package v2_6
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cachefly/cachefly-sdk-go/internal/httpclient"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// issueCert creates a certificate signed by parent, or a self-signed CA when
+// parent is nil.
+func issueCert(t *testing.T, parent *testCert, cn string, names []string, notAfter time.Time) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		DNSNames:              names,
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  names == nil,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key}
+}
+
+func pemCert(c *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Raw})
+}
+
+func TestCertificateBundle_Check(t *testing.T) {
+	year := time.Now().AddDate(1, 0, 0)
+	root := issueCert(t, nil, "Test Root", nil, year)
+	inter := issueCert(t, root, "Test Intermediate", nil, year)
+	leaf := issueCert(t, inter, "example.com", []string{"example.com", "*.example.com"}, year)
+
+	keyDER, _ := x509.MarshalECPrivateKey(leaf.key)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "cert.pem"), pemCert(leaf.cert), 0o600)
+	os.WriteFile(filepath.Join(dir, "chain.pem"), pemCert(inter.cert), 0o600)
+	os.WriteFile(filepath.Join(dir, "key.pem"), keyPEM, 0o600)
+
+	bundle, err := LoadCertificateBundle(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), filepath.Join(dir, "chain.pem"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(bundle.Chain) != 1 {
+		t.Fatalf("Expected one chain certificate, got %d", len(bundle.Chain))
+	}
+	if err := bundle.Check(CertificateCheckOptions{Domains: []string{"example.com", "www.example.com", "*.example.com"}, MinValidity: 30 * 24 * time.Hour}); err != nil {
+		t.Errorf("Expected a valid bundle, got %v", err)
+	}
+
+	other := issueCert(t, inter, "other", []string{"other.com"}, year)
+	bad := &CertificateBundle{Leaf: leaf.cert, Chain: []*x509.Certificate{root.cert, inter.cert}, Key: other.key}
+	err = bad.Check(CertificateCheckOptions{Domains: []string{"a.b.example.com", "*.other.com"}, Now: year.Add(time.Hour)})
+	for _, want := range []error{ErrCertificateKeyMismatch, ErrCertificateChainOrder, ErrCertificateNameMismatch, ErrCertificateExpired} {
+		if !errors.Is(err, want) {
+			t.Errorf("Expected %v in %v", want, err)
+		}
+	}
+
+	err = bundle.Check(CertificateCheckOptions{MinValidity: 2 * 365 * 24 * time.Hour})
+	if !errors.Is(err, ErrCertificateExpired) {
+		t.Errorf("Expected a certificate expiring too soon to be rejected, got %v", err)
+	}
+}
+
+func TestParseCertificateBundle_Errors(t *testing.T) {
+	leaf := issueCert(t, nil, "example.com", []string{"example.com"}, time.Now().AddDate(1, 0, 0))
+	keyDER, _ := x509.MarshalPKCS8PrivateKey(leaf.key)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+	if _, err := ParseCertificateBundle(nil, keyPEM); err == nil {
+		t.Error("Expected an error without a certificate")
+	}
+	if _, err := ParseCertificateBundle(pemCert(leaf.cert), pemCert(leaf.cert)); err == nil {
+		t.Error("Expected an error for a certificate given as key")
+	}
+	encrypted := pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: []byte{1}})
+	if _, err := ParseCertificateBundle(pemCert(leaf.cert), encrypted); err == nil {
+		t.Error("Expected an error for an encrypted key")
+	}
+}
+
+func TestCertificatesService_Upload(t *testing.T) {
+	var uploaded CreateCertificateRequest
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewDecoder(r.Body).Decode(&uploaded)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"_id":"cert-1","subjectCommonName":"example.com"}`))
+	}))
+	defer server.Close()
+	svc := &CertificatesService{Client: httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "t"})}
+
+	year := time.Now().AddDate(1, 0, 0)
+	root := issueCert(t, nil, "Test Root", nil, year)
+	leaf := issueCert(t, root, "example.com", []string{"example.com"}, year)
+	bundle, _ := NewCertificateBundle(leaf.cert, []*x509.Certificate{root.cert}, leaf.key)
+
+	if _, err := svc.Upload(context.Background(), bundle, CertificateCheckOptions{Domains: []string{"example.org"}}); !errors.Is(err, ErrCertificateNameMismatch) {
+		t.Errorf("Expected a name mismatch, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected nothing to be uploaded after a failed check, got %d requests", requests)
+	}
+
+	cert, err := svc.Upload(context.Background(), bundle, CertificateCheckOptions{Domains: []string{"example.com"}})
+	if err != nil || cert.ID != "cert-1" {
+		t.Fatalf("Expected the uploaded certificate, got %v, %v", cert, err)
+	}
+	sent, err := ParseCertificateBundle([]byte(uploaded.Certificate), []byte(uploaded.CertificateKey))
+	if err != nil {
+		t.Fatalf("Expected the request to hold a valid bundle, got %v", err)
+	}
+	if len(sent.Chain) != 1 || !sent.Leaf.Equal(leaf.cert) || sent.Check(CertificateCheckOptions{}) != nil {
+		t.Errorf("Expected the certificate, chain and key to be sent, got %+v", sent)
+	}
+}
@@ -0,0 +1,161 @@
+package v2_6
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ValidFrom returns the parsed NotBefore time, or the zero time if it is unset
+// or cannot be parsed.
+func (c Certificate) ValidFrom() time.Time {
+	return parseTaskTime(c.NotBefore)
+}
+
+// ExpiresAt returns the parsed NotAfter time, or the zero time if it is unset
+// or cannot be parsed.
+func (c Certificate) ExpiresAt() time.Time {
+	return parseTaskTime(c.NotAfter)
+}
+
+// CertificateExpiry is a certificate that is expired or expires soon.
+type CertificateExpiry struct {
+	Certificate
+	// ExpiresIn is the time left until expiry, negative once expired. It is
+	// zero if the expiry date is unknown and the certificate was reported by
+	// its Expired or Expiring flag.
+	ExpiresIn time.Duration `json:"expiresIn"`
+}
+
+// ExpiringCertificatesOptions configures CertificatesService.Expiring.
+type ExpiringCertificatesOptions struct {
+	// Within is the window to report certificates in, e.g. 30 days.
+	Within time.Duration
+	// IncludeUnused also reports certificates not used by any domain.
+	IncludeUnused bool
+	// Now is the time to compare against, defaulting to the current time.
+	Now time.Time
+}
+
+// Expiring scans all certificates and returns those that are expired or
+// expire within opts.Within, soonest first. Certificates whose expiry date
+// cannot be parsed are reported when the API flags them as expired or
+// expiring. The Services and Domains of each result are the ones impacted.
+func (s *CertificatesService) Expiring(ctx context.Context, opts ExpiringCertificatesOptions) ([]CertificateExpiry, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	var expiring []CertificateExpiry
+	for cert, err := range s.All(ctx, ListCertificatesOptions{}) {
+		if err != nil {
+			return nil, err
+		}
+		if !cert.InUse && !opts.IncludeUnused {
+			continue
+		}
+		notAfter := cert.ExpiresAt()
+		switch {
+		case !notAfter.IsZero() && notAfter.Sub(now) <= opts.Within:
+			expiring = append(expiring, CertificateExpiry{Certificate: cert, ExpiresIn: notAfter.Sub(now)})
+		case notAfter.IsZero() && (cert.Expired || cert.Expiring):
+			expiring = append(expiring, CertificateExpiry{Certificate: cert})
+		}
+	}
+	slices.SortStableFunc(expiring, func(a, b CertificateExpiry) int {
+		return a.ExpiresAt().Compare(b.ExpiresAt())
+	})
+	return expiring, nil
+}
+
+// RotateCertificateOptions configures CertificatesService.Rotate.
+type RotateCertificateOptions struct {
+	// Check holds additional checks for the replacement; the domains of the
+	// old certificate are always checked.
+	Check CertificateCheckOptions
+	// KeepOld leaves the old certificate in place even when it is unused.
+	KeepOld bool
+}
+
+// CertificateRotation is the result of CertificatesService.Rotate.
+type CertificateRotation struct {
+	Old *Certificate `json:"old"`
+	New *Certificate `json:"new"`
+	// Deleted reports whether the old certificate was deleted.
+	Deleted bool `json:"deleted"`
+}
+
+// Rotate uploads a replacement for a certificate. The replacement is checked
+// locally to cover every domain and subject name of the old certificate and,
+// once uploaded, the names reported by the API are checked again. The old
+// certificate is then deleted if it is not in use, unless opts.KeepOld is set.
+//
+// Rotate does not move domains to the replacement. When the old certificate
+// is still in use it is kept and Deleted is false; once its domains serve the
+// replacement, delete it with Delete.
+func (s *CertificatesService) Rotate(ctx context.Context, oldID string, bundle *CertificateBundle, opts RotateCertificateOptions) (*CertificateRotation, error) {
+	old, err := s.GetByID(ctx, oldID, "")
+	if err != nil {
+		return nil, err
+	}
+	rotation := &CertificateRotation{Old: old}
+
+	check := opts.Check
+	check.Domains = rotationDomains(old, check.Domains)
+	if rotation.New, err = s.Upload(ctx, bundle, check); err != nil {
+		return rotation, err
+	}
+	if missing := uncoveredDomains(rotation.New.SubjectNames, check.Domains); len(missing) > 0 {
+		return rotation, fmt.Errorf("%w: replacement certificate %s does not cover %s", ErrCertificateNameMismatch, rotation.New.ID, strings.Join(missing, ", "))
+	}
+	if opts.KeepOld || old.InUse {
+		return rotation, nil
+	}
+	if err := s.Delete(ctx, oldID); err != nil {
+		return rotation, err
+	}
+	rotation.Deleted = true
+	return rotation, nil
+}
+
+// rotationDomains returns the domains a replacement for old must cover.
+func rotationDomains(old *Certificate, extra []string) []string {
+	var domains []string
+	for _, list := range [][]string{old.Domains, old.SubjectNames, extra} {
+		for _, d := range list {
+			if d = strings.ToLower(strings.TrimSuffix(d, ".")); d != "" && !slices.Contains(domains, d) {
+				domains = append(domains, d)
+			}
+		}
+	}
+	if len(domains) == 0 && old.SubjectCommonName != "" {
+		domains = append(domains, strings.ToLower(old.SubjectCommonName))
+	}
+	return domains
+}
+
+// uncoveredDomains returns the domains not matched by any of names. A
+// wildcard name matches a single label; wildcard domains must be listed as is.
+func uncoveredDomains(names, domains []string) []string {
+	var missing []string
+	for _, d := range domains {
+		if !slices.ContainsFunc(names, func(n string) bool { return nameCovers(n, d) }) {
+			missing = append(missing, d)
+		}
+	}
+	return missing
+}
+
+func nameCovers(name, domain string) bool {
+	name, domain = strings.ToLower(name), strings.ToLower(domain)
+	if name == domain {
+		return true
+	}
+	if !strings.HasPrefix(name, "*.") || strings.HasPrefix(domain, "*.") {
+		return false
+	}
+	label, rest, ok := strings.Cut(domain, ".")
+	return ok && label != "" && "*."+rest == name
+}
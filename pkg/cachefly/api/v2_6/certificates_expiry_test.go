@@ -0,0 +1,164 @@
+package v2_6
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cachefly/cachefly-sdk-go/internal/httpclient"
+)
+
+func TestCertificatesService_Expiring(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"meta":{"count":5},"data":[
+			{"_id":"later","inUse":true,"notAfter":"2025-03-01T00:00:00Z"},
+			{"_id":"soon","inUse":true,"notAfter":"2025-01-20T00:00:00Z","services":["svc-1"],"domains":["cdn.example.com"]},
+			{"_id":"expired","inUse":true,"notAfter":"2024-12-01T00:00:00Z"},
+			{"_id":"unused","inUse":false,"notAfter":"2025-01-10T00:00:00Z"},
+			{"_id":"flagged","inUse":true,"notAfter":"","expiring":true}
+		]}`))
+	}))
+	defer server.Close()
+
+	svc := &CertificatesService{Client: httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "t"})}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	certs, err := svc.Expiring(context.Background(), ExpiringCertificatesOptions{Within: 30 * 24 * time.Hour, Now: now})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var ids []string
+	for _, c := range certs {
+		ids = append(ids, c.ID)
+	}
+	if strings.Join(ids, ",") != "flagged,expired,soon" {
+		t.Fatalf("Expected flagged, expired and soon, got %v", ids)
+	}
+	if certs[1].ExpiresIn >= 0 || certs[2].ExpiresIn != 19*24*time.Hour {
+		t.Errorf("Unexpected time left %v, %v", certs[1].ExpiresIn, certs[2].ExpiresIn)
+	}
+	if certs[2].Services[0] != "svc-1" || certs[2].Domains[0] != "cdn.example.com" {
+		t.Errorf("Expected the impacted services and domains, got %+v", certs[2])
+	}
+
+	certs, _ = svc.Expiring(context.Background(), ExpiringCertificatesOptions{Within: 30 * 24 * time.Hour, IncludeUnused: true, Now: now})
+	if len(certs) != 4 {
+		t.Errorf("Expected the unused certificate to be included, got %d", len(certs))
+	}
+}
+
+// rotationServer serves an old certificate for oldNames, reported as in use
+// when inUse is set, and accepts uploads and deletes. Rotate only deletes the
+// old certificate when it is already unused.
+func rotationServer(oldNames string, inUse bool) (*httptest.Server, *[]string) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+strings.TrimPrefix(r.URL.Path, "/api/2.6"))
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(map[string]interface{}{"_id": "old", "inUse": inUse, "subjectNames": strings.Split(oldNames, ","), "domains": []string{"cdn.example.com"}})
+		case http.MethodPost:
+			var req CreateCertificateRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			sent, _ := ParseCertificateBundle([]byte(req.Certificate), []byte(req.CertificateKey))
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{"_id": "new", "subjectNames": sent.Leaf.DNSNames})
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	return server, &requests
+}
+
+func TestCertificatesService_Rotate(t *testing.T) {
+	year := time.Now().AddDate(1, 0, 0)
+	root := issueCert(t, nil, "Test Root", nil, year)
+	leaf := issueCert(t, root, "example.com", []string{"example.com", "*.example.com"}, year)
+	bundle, _ := NewCertificateBundle(leaf.cert, []*x509.Certificate{root.cert}, leaf.key)
+
+	server, requests := rotationServer("example.com,*.example.com", false)
+	defer server.Close()
+	svc := &CertificatesService{Client: httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "t"})}
+
+	rotation, err := svc.Rotate(context.Background(), "old", bundle, RotateCertificateOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !rotation.Deleted || rotation.New.ID != "new" {
+		t.Errorf("Unexpected rotation %+v", rotation)
+	}
+	want := "GET /certificates/old,POST /certificates,DELETE /certificates/old"
+	if got := strings.Join(*requests, ","); got != want {
+		t.Errorf("Expected requests %s, got %s", want, got)
+	}
+}
+
+func TestCertificatesService_RotateInUse(t *testing.T) {
+	year := time.Now().AddDate(1, 0, 0)
+	leaf := issueCert(t, nil, "example.com", []string{"example.com", "*.example.com"}, year)
+	bundle, _ := NewCertificateBundle(leaf.cert, nil, leaf.key)
+
+	server, requests := rotationServer("example.com", true)
+	defer server.Close()
+	svc := &CertificatesService{Client: httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "t"})}
+
+	// the old certificate never goes out of use; Rotate must not wait for it
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rotation, err := svc.Rotate(ctx, "old", bundle, RotateCertificateOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rotation.Deleted || !rotation.Old.InUse || rotation.New.ID != "new" {
+		t.Errorf("Expected the old certificate to be kept, got %+v", rotation)
+	}
+	want := "GET /certificates/old,POST /certificates"
+	if got := strings.Join(*requests, ","); got != want {
+		t.Errorf("Expected requests %s, got %s", want, got)
+	}
+}
+
+func TestCertificatesService_RotateUncovered(t *testing.T) {
+	year := time.Now().AddDate(1, 0, 0)
+	leaf := issueCert(t, nil, "example.com", []string{"example.com"}, year)
+	bundle, _ := NewCertificateBundle(leaf.cert, nil, leaf.key)
+
+	server, requests := rotationServer("example.com,www.example.org", false)
+	defer server.Close()
+	svc := &CertificatesService{Client: httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "t"})}
+
+	_, err := svc.Rotate(context.Background(), "old", bundle, RotateCertificateOptions{})
+	if !errors.Is(err, ErrCertificateNameMismatch) || !strings.Contains(err.Error(), "www.example.org") {
+		t.Errorf("Expected a name mismatch for www.example.org, got %v", err)
+	}
+	if len(*requests) != 1 {
+		t.Errorf("Expected nothing to be uploaded or deleted, got %v", *requests)
+	}
+}
+
+func TestNameCovers(t *testing.T) {
+	tests := []struct {
+		name, domain string
+		want         bool
+	}{
+		{"example.com", "EXAMPLE.com", true},
+		{"*.example.com", "www.example.com", true},
+		{"*.example.com", "a.b.example.com", false},
+		{"*.example.com", "example.com", false},
+		{"*.example.com", "*.example.com", true},
+		{"www.example.com", "*.example.com", false},
+	}
+	for _, tt := range tests {
+		if got := nameCovers(tt.name, tt.domain); got != tt.want {
+			t.Errorf("nameCovers(%q, %q) = %v, expected %v", tt.name, tt.domain, got, tt.want)
+		}
+	}
+}
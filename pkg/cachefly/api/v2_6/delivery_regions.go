@@ -3,6 +3,7 @@ package v2_6
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 
@@ -73,3 +74,15 @@ func (s *DeliveryRegionsService) List(ctx context.Context, opts ListDeliveryRegi
 	}
 	return &resp, nil
 }
+
+// All iterates over all delivery regions, fetching opts.Limit items per page.
+func (s *DeliveryRegionsService) All(ctx context.Context, opts ListDeliveryRegionsOptions) iter.Seq2[DeliveryRegion, error] {
+	return paginate(ctx, opts.Offset, opts.Limit, func(ctx context.Context, offset, limit int) ([]DeliveryRegion, MetaInfo, error) {
+		opts.Offset, opts.Limit = offset, limit
+		resp, err := s.List(ctx, opts)
+		if err != nil {
+			return nil, MetaInfo{}, err
+		}
+		return resp.Regions, MetaInfo{Limit: resp.Meta.Limit, Offset: resp.Meta.Offset, Count: resp.Meta.Count}, nil
+	})
+}
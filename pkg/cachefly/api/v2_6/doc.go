@@ -22,6 +22,10 @@
 // - AvailabilityService: Checks availability of domains, usernames, services, SAML
 // - SAMLService: Manages SAML configuration operations
 //
+// List endpoints return a single page together with MetaInfo. Each of them
+// also has an All method returning an iter.Seq2 that fetches further pages on
+// demand; ListAll and ListN collect such an iterator into a slice.
+//
 // This package is typically not imported directly. Instead, use the
 // main cachefly package which provides a unified client interface.
 //
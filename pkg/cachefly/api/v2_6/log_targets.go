@@ -3,6 +3,7 @@ package v2_6
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 
@@ -131,6 +132,18 @@ func (s *LogTargetsService) List(ctx context.Context, opts ListLogTargetsOptions
 	return &resp, nil
 }
 
+// All iterates over all log targets, fetching opts.Limit items per page.
+func (s *LogTargetsService) All(ctx context.Context, opts ListLogTargetsOptions) iter.Seq2[LogTarget, error] {
+	return paginate(ctx, opts.Offset, opts.Limit, func(ctx context.Context, offset, limit int) ([]LogTarget, MetaInfo, error) {
+		opts.Offset, opts.Limit = offset, limit
+		resp, err := s.List(ctx, opts)
+		if err != nil {
+			return nil, MetaInfo{}, err
+		}
+		return resp.LogTargets, resp.Meta, nil
+	})
+}
+
 // Create creates a new log target.
 func (s *LogTargetsService) Create(ctx context.Context, req CreateLogTargetRequest) (*LogTarget, error) {
 	endpoint := "/logtargets"
@@ -3,6 +3,7 @@ package v2_6
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 
@@ -113,6 +114,18 @@ func (s *OriginsService) List(ctx context.Context, opts ListOriginsOptions) (*Li
 	return &resp, nil
 }
 
+// All iterates over all origins, fetching opts.Limit items per page.
+func (s *OriginsService) All(ctx context.Context, opts ListOriginsOptions) iter.Seq2[Origin, error] {
+	return paginate(ctx, opts.Offset, opts.Limit, func(ctx context.Context, offset, limit int) ([]Origin, MetaInfo, error) {
+		opts.Offset, opts.Limit = offset, limit
+		resp, err := s.List(ctx, opts)
+		if err != nil {
+			return nil, MetaInfo{}, err
+		}
+		return resp.Origins, resp.Meta, nil
+	})
+}
+
 // Create adds a new origin.
 func (s *OriginsService) Create(ctx context.Context, req CreateOriginRequest) (*Origin, error) {
 	endpoint := "/origins"
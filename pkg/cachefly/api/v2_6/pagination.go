@@ -0,0 +1,102 @@
+package v2_6
+
+import (
+	"context"
+	"iter"
+)
+
+// DefaultPageSize is the page size used by All iterators when the list
+// options do not set a Limit.
+const DefaultPageSize = 100
+
+// pageFetcher fetches a single page of a list endpoint.
+type pageFetcher[T any] func(ctx context.Context, offset, limit int) ([]T, MetaInfo, error)
+
+// paginate returns an iterator over every item of a list endpoint, fetching
+// pages of limit items starting at start. Iteration ends after the last
+// page, on the first error, or as soon as the consumer stops ranging; no
+// further pages are requested after that.
+func paginate[T any](ctx context.Context, start, limit int, fetch pageFetcher[T]) iter.Seq2[T, error] {
+	if start < 0 {
+		start = 0
+	}
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+
+	return func(yield func(T, error) bool) {
+		// each range starts over from the first page
+		offset := start
+		for {
+			if err := ctx.Err(); err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			items, meta, err := fetch(ctx, offset, limit)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			offset += len(items)
+			if len(items) == 0 {
+				return
+			}
+			// The API may return fewer items than requested when it caps the
+			// page size, so a short page only ends iteration when there is no
+			// total count to go by.
+			if meta.Count > 0 {
+				if offset >= meta.Count {
+					return
+				}
+			} else if len(items) < limit {
+				return
+			}
+		}
+	}
+}
+
+// ListAll collects every item produced by an All iterator into a slice. It
+// returns the items collected so far together with the first error.
+//
+// Example:
+//
+//	services, err := v2_6.ListAll(client.Services.All(ctx, v2_6.ListOptions{Limit: 50}))
+func ListAll[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var all []T
+	for item, err := range seq {
+		if err != nil {
+			return all, err
+		}
+		all = append(all, item)
+	}
+	return all, nil
+}
+
+// ListN collects at most n items produced by an All iterator, stopping the
+// pagination early once n items have been read.
+func ListN[T any](seq iter.Seq2[T, error], n int) ([]T, error) {
+	var items []T
+	if n <= 0 {
+		return items, nil
+	}
+	for item, err := range seq {
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+		if len(items) >= n {
+			break
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,233 @@
+package v2_6
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cachefly/cachefly-sdk-go/internal/httpclient"
+)
+
+// newPagedServer serves total services in pages according to offset/limit and
+// records the offsets requested.
+func newPagedServer(t *testing.T, total int, offsets *[]int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/2.6/services" {
+			t.Errorf("Expected path /api/2.6/services, got %s", r.URL.Path)
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		*offsets = append(*offsets, offset)
+
+		var items []string
+		for i := offset; i < total && i < offset+limit; i++ {
+			items = append(items, fmt.Sprintf(`{"_id":"svc-%d"}`, i))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"meta":{"limit":%d,"offset":%d,"count":%d},"data":[%s]}`, limit, offset, total, strings.Join(items, ","))
+	}))
+}
+
+func TestServicesService_All(t *testing.T) {
+	var offsets []int
+	server := newPagedServer(t, 25, &offsets)
+	defer server.Close()
+
+	client := httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "test-token"})
+	svc := &ServicesService{Client: client}
+
+	services, err := ListAll(svc.All(context.Background(), ListOptions{Limit: 10}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(services) != 25 {
+		t.Fatalf("Expected 25 services, got %d", len(services))
+	}
+	if services[24].ID != "svc-24" {
+		t.Errorf("Expected last service svc-24, got %s", services[24].ID)
+	}
+	if fmt.Sprint(offsets) != "[0 10 20]" {
+		t.Errorf("Expected offsets [0 10 20], got %v", offsets)
+	}
+}
+
+func TestServicesService_All_ExactPages(t *testing.T) {
+	var offsets []int
+	server := newPagedServer(t, 20, &offsets)
+	defer server.Close()
+
+	client := httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "test-token"})
+	svc := &ServicesService{Client: client}
+
+	services, err := ListAll(svc.All(context.Background(), ListOptions{Limit: 10}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(services) != 20 {
+		t.Errorf("Expected 20 services, got %d", len(services))
+	}
+	// meta.count tells the iterator there is no third page
+	if len(offsets) != 2 {
+		t.Errorf("Expected 2 page requests, got %v", offsets)
+	}
+}
+
+func TestServicesService_All_CappedPageSize(t *testing.T) {
+	var offsets []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		offsets = append(offsets, offset)
+
+		// the server returns at most 4 items whatever limit is requested
+		var items []string
+		for i := offset; i < 10 && i < offset+4; i++ {
+			items = append(items, fmt.Sprintf(`{"_id":"svc-%d"}`, i))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"meta":{"limit":4,"offset":%d,"count":10},"data":[%s]}`, offset, strings.Join(items, ","))
+	}))
+	defer server.Close()
+
+	client := httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "test-token"})
+	svc := &ServicesService{Client: client}
+
+	services, err := ListAll(svc.All(context.Background(), ListOptions{Limit: 10}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(services) != 10 {
+		t.Fatalf("Expected 10 services, got %d", len(services))
+	}
+	if fmt.Sprint(offsets) != "[0 4 8]" {
+		t.Errorf("Expected offsets [0 4 8], got %v", offsets)
+	}
+}
+
+func TestServicesService_All_EarlyStop(t *testing.T) {
+	var offsets []int
+	server := newPagedServer(t, 100, &offsets)
+	defer server.Close()
+
+	client := httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "test-token"})
+	svc := &ServicesService{Client: client}
+
+	services, err := ListN(svc.All(context.Background(), ListOptions{Limit: 10}), 15)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(services) != 15 {
+		t.Errorf("Expected 15 services, got %d", len(services))
+	}
+	if len(offsets) != 2 {
+		t.Errorf("Expected 2 page requests, got %v", offsets)
+	}
+}
+
+func TestServicesService_All_Reuse(t *testing.T) {
+	var offsets []int
+	server := newPagedServer(t, 25, &offsets)
+	defer server.Close()
+
+	client := httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "test-token"})
+	svc := &ServicesService{Client: client}
+
+	seq := svc.All(context.Background(), ListOptions{Limit: 10})
+	for i := 0; i < 2; i++ {
+		services, err := ListAll(seq)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(services) != 25 {
+			t.Errorf("Expected 25 services on range %d, got %d", i+1, len(services))
+		}
+	}
+	if fmt.Sprint(offsets) != "[0 10 20 0 10 20]" {
+		t.Errorf("Expected offsets [0 10 20 0 10 20], got %v", offsets)
+	}
+}
+
+func TestScriptDefinitionsService_All(t *testing.T) {
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/2.6/scriptConfigDefinitions" {
+			t.Errorf("Expected path /api/2.6/scriptConfigDefinitions, got %s", r.URL.Path)
+		}
+		offset := r.URL.Query().Get("offset")
+		offsets = append(offsets, offset)
+		if offset == "0" {
+			w.Write([]byte(`{"meta":{"limit":2,"offset":0,"count":3},"data":[{"_id":"def-0"},{"_id":"def-1"}]}`))
+			return
+		}
+		w.Write([]byte(`{"meta":{"limit":2,"offset":2,"count":3},"data":[{"_id":"def-2"}]}`))
+	}))
+	defer server.Close()
+
+	client := httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "test-token"})
+	svc := &ScriptDefinitionsService{Client: client}
+
+	defs, err := ListAll(svc.All(context.Background(), ListScriptDefinitionsOptions{Limit: 2}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(defs) != 3 || defs[2].ID != "def-2" {
+		t.Errorf("Expected 3 definitions ending with def-2, got %v", defs)
+	}
+	if fmt.Sprint(offsets) != "[0 2]" {
+		t.Errorf("Expected offsets [0 2], got %v", offsets)
+	}
+}
+
+func TestServicesService_All_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "0" {
+			w.Write([]byte(`{"meta":{"limit":1,"offset":0,"count":3},"data":[{"_id":"svc-0"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "test-token"})
+	svc := &ServicesService{Client: client}
+
+	services, err := ListAll(svc.All(context.Background(), ListOptions{Limit: 1}))
+	if err == nil {
+		t.Fatal("Expected error from second page")
+	}
+	if len(services) != 1 {
+		t.Errorf("Expected items from the first page to be returned, got %d", len(services))
+	}
+}
+
+func TestServiceDomainsService_All_DefaultPageSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/2.6/services/svc-1/domains" {
+			t.Errorf("Expected path /api/2.6/services/svc-1/domains, got %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("limit"); got != strconv.Itoa(DefaultPageSize) {
+			t.Errorf("Expected default limit %d, got %s", DefaultPageSize, got)
+		}
+		w.Write([]byte(`{"meta":{"limit":100,"offset":0,"count":1},"data":[{"_id":"d1","name":"example.com"}]}`))
+	}))
+	defer server.Close()
+
+	client := httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "test-token"})
+	svc := &ServiceDomainsService{Client: client}
+
+	var names []string
+	for d, err := range svc.All(context.Background(), "svc-1", ListServiceDomainsOptions{}) {
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		names = append(names, d.Name)
+	}
+	if len(names) != 1 || names[0] != "example.com" {
+		t.Errorf("Unexpected domains %v", names)
+	}
+}
@@ -3,6 +3,7 @@ package v2_6
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 
@@ -103,6 +104,18 @@ func (s *ScriptConfigsService) List(ctx context.Context, opts ListScriptConfigsO
 	return &resp, nil
 }
 
+// All iterates over all script configs, fetching opts.Limit items per page.
+func (s *ScriptConfigsService) All(ctx context.Context, opts ListScriptConfigsOptions) iter.Seq2[ScriptConfig, error] {
+	return paginate(ctx, opts.Offset, opts.Limit, func(ctx context.Context, offset, limit int) ([]ScriptConfig, MetaInfo, error) {
+		opts.Offset, opts.Limit = offset, limit
+		resp, err := s.List(ctx, opts)
+		if err != nil {
+			return nil, MetaInfo{}, err
+		}
+		return resp.Configs, resp.Meta, nil
+	})
+}
+
 // Create posts a new script config.
 func (s *ScriptConfigsService) Create(ctx context.Context, req CreateScriptConfigRequest) (*ScriptConfig, error) {
 	var created ScriptConfig
@@ -3,6 +3,7 @@ package v2_6
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 
@@ -92,6 +93,19 @@ func (s *ScriptDefinitionsService) List(ctx context.Context, opts ListScriptDefi
 	return &resp, nil
 }
 
+// All iterates over all script config definitions, fetching opts.Limit items
+// per page.
+func (s *ScriptDefinitionsService) All(ctx context.Context, opts ListScriptDefinitionsOptions) iter.Seq2[ScriptDefinition, error] {
+	return paginate(ctx, opts.Offset, opts.Limit, func(ctx context.Context, offset, limit int) ([]ScriptDefinition, MetaInfo, error) {
+		opts.Offset, opts.Limit = offset, limit
+		resp, err := s.List(ctx, opts)
+		if err != nil {
+			return nil, MetaInfo{}, err
+		}
+		return resp.Definitions, MetaInfo(resp.Meta), nil
+	})
+}
+
 // GetByID retrieves a script config definition by its ID.
 // GET /scriptConfigDefinitions/{id}
 func (s *ScriptDefinitionsService) GetByID(ctx context.Context, id string) (*ScriptDefinition, error) {
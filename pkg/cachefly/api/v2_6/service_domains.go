@@ -3,6 +3,7 @@ package v2_6
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 
@@ -85,6 +86,18 @@ func (s *ServiceDomainsService) List(ctx context.Context, sid string, opts ListS
 	return &resp, nil
 }
 
+// All iterates over all domains of a service, fetching opts.Limit items per page.
+func (s *ServiceDomainsService) All(ctx context.Context, sid string, opts ListServiceDomainsOptions) iter.Seq2[ServiceDomain, error] {
+	return paginate(ctx, opts.Offset, opts.Limit, func(ctx context.Context, offset, limit int) ([]ServiceDomain, MetaInfo, error) {
+		opts.Offset, opts.Limit = offset, limit
+		resp, err := s.List(ctx, sid, opts)
+		if err != nil {
+			return nil, MetaInfo{}, err
+		}
+		return resp.Domains, resp.Meta, nil
+	})
+}
+
 // Create adds a new domain to the service.
 func (s *ServiceDomainsService) Create(ctx context.Context, sid string, req CreateServiceDomainRequest) (*ServiceDomain, error) {
 	if sid == "" {
@@ -0,0 +1,234 @@
+package v2_6
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Domain validation modes.
+const (
+	DomainValidationModeHTTP = "HTTP"
+	DomainValidationModeDNS  = "DNS"
+)
+
+// DomainValidationStatus is the validation state of a service domain, see
+// ServiceDomain.ValidationState.
+type DomainValidationStatus string
+
+const (
+	DomainValidationPending    DomainValidationStatus = "PENDING"
+	DomainValidationValidating DomainValidationStatus = "VALIDATING"
+	DomainValidationValidated  DomainValidationStatus = "VALIDATED"
+	DomainValidationFailed     DomainValidationStatus = "FAILED"
+)
+
+// ErrUnknownDomainValidationStatus is returned by WaitForValidation when a
+// domain reports a status other than the DomainValidation constants.
+var ErrUnknownDomainValidationStatus = errors.New("unknown domain validation status")
+
+// Done reports whether validation is no longer in progress. Statuses other
+// than the known ones count as done, so that waiting for them ends.
+func (s DomainValidationStatus) Done() bool {
+	return s != DomainValidationPending && s != DomainValidationValidating
+}
+
+// ValidationState returns the validation status of the domain.
+func (d ServiceDomain) ValidationState() DomainValidationStatus {
+	return DomainValidationStatus(strings.ToUpper(d.ValidationStatus))
+}
+
+// DefaultDomainValidationPollInterval is the poll interval used by
+// WaitForValidation when none is given.
+const DefaultDomainValidationPollInterval = 10 * time.Second
+
+// DNSRecord is a DNS record that must exist for a domain to validate.
+type DNSRecord struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// String formats the record as a zone file line.
+func (r DNSRecord) String() string {
+	return fmt.Sprintf("%s. %s %s.", r.Name, r.Type, r.Value)
+}
+
+// ValidationRecords returns the DNS records the domain needs before it can be
+// validated. With DNS validation, that is a CNAME from _acme-challenge.<name>
+// to the validation target. HTTP validation needs no record of its own and
+// returns none; see HTTPChallenge.
+func (d ServiceDomain) ValidationRecords() ([]DNSRecord, error) {
+	if d.ValidationTarget == "" {
+		return nil, fmt.Errorf("domain %s has no validation target", d.Name)
+	}
+	switch strings.ToUpper(d.ValidationMode) {
+	case DomainValidationModeDNS:
+		target := strings.TrimSuffix(d.ValidationTarget, ".")
+		return []DNSRecord{{Type: "CNAME", Name: "_acme-challenge." + d.Name, Value: target}}, nil
+	case DomainValidationModeHTTP:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported validation mode %q of domain %s", d.ValidationMode, d.Name)
+	}
+}
+
+// HTTPChallenge is what HTTP validation of a domain requires: requests for
+// the ACME challenge under URL must reach CacheFly. The domain must therefore
+// be served by CacheFly through Target, e.g. with a CNAME for a subdomain or
+// with the ALIAS or A records offered by the DNS provider at a zone apex.
+type HTTPChallenge struct {
+	URL    string
+	Target string
+}
+
+// HTTPChallenge returns the HTTP challenge requirement of a domain that uses
+// HTTP validation.
+func (d ServiceDomain) HTTPChallenge() (*HTTPChallenge, error) {
+	if !strings.EqualFold(d.ValidationMode, DomainValidationModeHTTP) {
+		return nil, fmt.Errorf("domain %s does not use HTTP validation", d.Name)
+	}
+	if d.ValidationTarget == "" {
+		return nil, fmt.Errorf("domain %s has no validation target", d.Name)
+	}
+	return &HTTPChallenge{
+		URL:    "http://" + d.Name + "/.well-known/acme-challenge/",
+		Target: strings.TrimSuffix(d.ValidationTarget, "."),
+	}, nil
+}
+
+// DomainResolver looks up DNS records; *net.Resolver implements it.
+type DomainResolver interface {
+	LookupCNAME(ctx context.Context, host string) (string, error)
+}
+
+// DomainRecordError lists the validation records that are missing or point
+// elsewhere.
+type DomainRecordError struct {
+	Domain  string
+	Records []DNSRecord
+	// Found holds the value each record resolved to, or the lookup error.
+	Found []string
+}
+
+func (e *DomainRecordError) Error() string {
+	parts := make([]string, len(e.Records))
+	for i, r := range e.Records {
+		parts[i] = fmt.Sprintf("%s %s should point to %s, found %s", r.Name, r.Type, r.Value, e.Found[i])
+	}
+	return fmt.Sprintf("domain %s is not ready for validation: %s", e.Domain, strings.Join(parts, "; "))
+}
+
+// CheckValidationRecords resolves the validation records of a domain and
+// returns a *DomainRecordError if any does not point to its target. A record
+// is accepted when it resolves to the target or to the canonical name of the
+// target. Domains using HTTP validation have no records to check.
+func CheckValidationRecords(ctx context.Context, resolver DomainResolver, d ServiceDomain) error {
+	records, err := d.ValidationRecords()
+	if err != nil {
+		return err
+	}
+	recErr := &DomainRecordError{Domain: d.Name}
+	for _, r := range records {
+		got, err := resolver.LookupCNAME(ctx, r.Name)
+		if err != nil {
+			recErr.Records = append(recErr.Records, r)
+			recErr.Found = append(recErr.Found, err.Error())
+			continue
+		}
+		got = strings.TrimSuffix(got, ".")
+		if strings.EqualFold(got, r.Value) {
+			continue
+		}
+		if canonical, err := resolver.LookupCNAME(ctx, r.Value); err == nil && strings.EqualFold(got, strings.TrimSuffix(canonical, ".")) {
+			continue
+		}
+		recErr.Records = append(recErr.Records, r)
+		recErr.Found = append(recErr.Found, got)
+	}
+	if len(recErr.Records) > 0 {
+		return recErr
+	}
+	return nil
+}
+
+// DomainValidationError is returned when validation of a domain fails.
+type DomainValidationError struct {
+	Domain ServiceDomain
+}
+
+func (e *DomainValidationError) Error() string {
+	return fmt.Sprintf("validation of domain %s failed", e.Domain.Name)
+}
+
+// ValidateDomainOptions configures ServiceDomainsService.Validate.
+type ValidateDomainOptions struct {
+	// Resolver, when set, is used to check the validation records before
+	// validation is requested, e.g. net.DefaultResolver.
+	Resolver DomainResolver
+	// PollInterval is the interval between status checks.
+	PollInterval time.Duration
+}
+
+// Validate runs the validation of a domain: it checks the validation records
+// with opts.Resolver if set, signals that the domain is ready and waits until
+// validation is done. A domain that is already validated is returned as is.
+func (s *ServiceDomainsService) Validate(ctx context.Context, sid, id string, opts ValidateDomainOptions) (*ServiceDomain, error) {
+	d, err := s.GetByID(ctx, sid, id, "")
+	if err != nil {
+		return nil, err
+	}
+	if d.ValidationState() == DomainValidationValidated {
+		return d, nil
+	}
+	if opts.Resolver != nil {
+		if err := CheckValidationRecords(ctx, opts.Resolver, *d); err != nil {
+			return d, err
+		}
+	}
+	if d.ValidationState() != DomainValidationValidating {
+		if _, err := s.ValidationReady(ctx, sid, id); err != nil {
+			return d, err
+		}
+	}
+	return s.WaitForValidation(ctx, sid, id, opts.PollInterval)
+}
+
+// WaitForValidation polls a domain until its validation is done. It returns a
+// *DomainValidationError if validation fails, an error wrapping
+// ErrUnknownDomainValidationStatus for a status it does not know, and an
+// error wrapping ctx.Err() with the last state seen if ctx is done first.
+func (s *ServiceDomainsService) WaitForValidation(ctx context.Context, sid, id string, pollInterval time.Duration) (*ServiceDomain, error) {
+	if pollInterval <= 0 {
+		pollInterval = DefaultDomainValidationPollInterval
+	}
+	var last *ServiceDomain
+	for {
+		d, err := s.GetByID(ctx, sid, id, "")
+		if err != nil {
+			if ctx.Err() != nil {
+				return last, fmt.Errorf("waiting for validation of domain %s: %w", id, ctx.Err())
+			}
+			return last, err
+		}
+		last = d
+		switch status := d.ValidationState(); {
+		case status == DomainValidationValidated:
+			return d, nil
+		case status == DomainValidationFailed:
+			return d, &DomainValidationError{Domain: *d}
+		case status.Done():
+			return d, fmt.Errorf("%w %q of domain %s", ErrUnknownDomainValidationStatus, d.ValidationStatus, d.Name)
+		}
+
+		timer := time.NewTimer(pollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return last, fmt.Errorf("waiting for validation of domain %s: %w", id, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package v2_6
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cachefly/cachefly-sdk-go/internal/httpclient"
+)
+
+// fakeResolver answers CNAME lookups from a map.
+type fakeResolver map[string]string
+
+func (r fakeResolver) LookupCNAME(ctx context.Context, host string) (string, error) {
+	if v, ok := r[host]; ok {
+		return v + ".", nil
+	}
+	return "", fmt.Errorf("no such host %s", host)
+}
+
+func TestServiceDomain_ValidationRecords(t *testing.T) {
+	d := ServiceDomain{Name: "cdn.example.com", ValidationMode: "DNS", ValidationTarget: "abc.validation.cachefly.net."}
+	records, err := d.ValidationRecords()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(records) != 1 || records[0].String() != "_acme-challenge.cdn.example.com. CNAME abc.validation.cachefly.net." {
+		t.Errorf("Unexpected records %v", records)
+	}
+
+	if _, err := d.HTTPChallenge(); err == nil {
+		t.Error("Expected no HTTP challenge for DNS validation")
+	}
+
+	d.ValidationMode = "HTTP"
+	if records, err := d.ValidationRecords(); err != nil || len(records) != 0 {
+		t.Errorf("Expected no records for HTTP validation, got %v (%v)", records, err)
+	}
+	challenge, err := d.HTTPChallenge()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if challenge.URL != "http://cdn.example.com/.well-known/acme-challenge/" || challenge.Target != "abc.validation.cachefly.net" {
+		t.Errorf("Unexpected HTTP challenge %+v", challenge)
+	}
+
+	d.ValidationMode = "MANUAL"
+	if _, err := d.ValidationRecords(); err == nil {
+		t.Error("Expected an error for an unsupported mode")
+	}
+}
+
+func TestCheckValidationRecords(t *testing.T) {
+	d := ServiceDomain{Name: "cdn.example.com", ValidationMode: "DNS", ValidationTarget: "t.cachefly.net"}
+	ctx := context.Background()
+
+	if err := CheckValidationRecords(ctx, fakeResolver{"_acme-challenge.cdn.example.com": "t.cachefly.net"}, d); err != nil {
+		t.Errorf("Expected a direct CNAME to pass, got %v", err)
+	}
+	if err := CheckValidationRecords(ctx, fakeResolver{"_acme-challenge.cdn.example.com": "edge.cachefly.net", "t.cachefly.net": "edge.cachefly.net"}, d); err != nil {
+		t.Errorf("Expected a record resolving to the target's canonical name to pass, got %v", err)
+	}
+
+	err := CheckValidationRecords(ctx, fakeResolver{"_acme-challenge.cdn.example.com": "old-cdn.example.net"}, d)
+	var recErr *DomainRecordError
+	if !errors.As(err, &recErr) || recErr.Found[0] != "old-cdn.example.net" {
+		t.Errorf("Expected *DomainRecordError, got %v", err)
+	}
+	if err := CheckValidationRecords(ctx, fakeResolver{}, d); !errors.As(err, &recErr) {
+		t.Errorf("Expected *DomainRecordError for a missing record, got %v", err)
+	}
+
+	d.ValidationMode = "HTTP"
+	if err := CheckValidationRecords(ctx, fakeResolver{}, d); err != nil {
+		t.Errorf("Expected nothing to check for HTTP validation, got %v", err)
+	}
+}
+
+func TestServiceDomainsService_Validate(t *testing.T) {
+	status := "PENDING"
+	polls := 0
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+strings.TrimPrefix(r.URL.Path, "/api/2.6"))
+		switch {
+		case r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/validationReady"):
+			status = "VALIDATING"
+		case status == "VALIDATING":
+			if polls++; polls == 3 {
+				status = "VALIDATED"
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"_id":"d1","name":"cdn.example.com","validationMode":"DNS","validationTarget":"t.cachefly.net","validationStatus":%q}`, status)
+	}))
+	defer server.Close()
+
+	svc := &ServiceDomainsService{Client: httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "t"})}
+	ctx := context.Background()
+
+	_, err := svc.Validate(ctx, "svc-1", "d1", ValidateDomainOptions{Resolver: fakeResolver{}, PollInterval: time.Millisecond})
+	var recErr *DomainRecordError
+	if !errors.As(err, &recErr) {
+		t.Fatalf("Expected *DomainRecordError, got %v", err)
+	}
+	if len(requests) != 1 {
+		t.Errorf("Expected validation not to be requested with missing records, got %v", requests)
+	}
+
+	d, err := svc.Validate(ctx, "svc-1", "d1", ValidateDomainOptions{Resolver: fakeResolver{"_acme-challenge.cdn.example.com": "t.cachefly.net"}, PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if d.ValidationState() != DomainValidationValidated {
+		t.Errorf("Expected VALIDATED, got %s", d.ValidationStatus)
+	}
+	if requests[2] != "PUT /services/svc-1/domains/d1/validationReady" {
+		t.Errorf("Expected validation to be requested, got %v", requests)
+	}
+}
+
+func TestServiceDomainsService_WaitForValidation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"_id":"d1","name":"cdn.example.com","validationStatus":"FAILED"}`))
+	}))
+	defer server.Close()
+
+	svc := &ServiceDomainsService{Client: httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "t"})}
+	_, err := svc.WaitForValidation(context.Background(), "svc-1", "d1", time.Millisecond)
+	var valErr *DomainValidationError
+	if !errors.As(err, &valErr) || valErr.Domain.Name != "cdn.example.com" {
+		t.Errorf("Expected *DomainValidationError, got %v", err)
+	}
+}
+
+func TestServiceDomainsService_WaitForValidation_UnknownStatus(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"_id":"d1","name":"cdn.example.com","validationStatus":"EXPIRED"}`))
+	}))
+	defer server.Close()
+
+	svc := &ServiceDomainsService{Client: httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "t"})}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	d, err := svc.WaitForValidation(ctx, "svc-1", "d1", time.Millisecond)
+	if !errors.Is(err, ErrUnknownDomainValidationStatus) || !strings.Contains(err.Error(), `"EXPIRED"`) {
+		t.Errorf("Expected an unknown status error, got %v", err)
+	}
+	if d == nil || polls != 1 {
+		t.Errorf("Expected to stop after the first poll, got %d polls", polls)
+	}
+}
@@ -3,6 +3,7 @@ package v2_6
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/url"
 	"slices"
 	"strconv"
@@ -129,27 +130,33 @@ func (s *ServiceOptionsService) UpdateOptions(ctx context.Context, id string, op
 		return nil, fmt.Errorf("id is required")
 	}
 
-	var protectServeKeyEnabled *bool
+	if err := s.validateUpdate(ctx, id, options); err != nil {
+		return nil, err
+	}
+	return s.updateOptions(ctx, id, options)
+}
 
-	if val, exists := options["protectServeKeyEnabled"]; exists {
-		if boolVal, ok := val.(bool); ok {
-			protectServeKeyEnabled = &boolVal
-			// Remove from options since it's handled separately
-			delete(options, "protectServeKeyEnabled")
-		}
+// validateUpdate validates options, except protectServeKeyEnabled, against
+// the options metadata of the service.
+func (s *ServiceOptionsService) validateUpdate(ctx context.Context, id string, options ServiceOptions) error {
+	rest, _ := splitProtectServe(options)
+	if len(rest) == 0 {
+		return nil
+	}
+	metadata, err := s.GetOptionsMetadata(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get options metadata: %w", err)
 	}
+	return s.validateOptions(rest, metadata)
+}
+
+// updateOptions sends validated options, applying protectServeKeyEnabled
+// through the ProtectServe key endpoints.
+func (s *ServiceOptionsService) updateOptions(ctx context.Context, id string, options ServiceOptions) (ServiceOptions, error) {
+	options, protectServeKeyEnabled := splitProtectServe(options)
 
 	var updated ServiceOptions
 	if len(options) > 0 {
-		metadata, err := s.GetOptionsMetadata(ctx, id)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get options metadata: %w", err)
-		}
-
-		if err := s.validateOptions(options, metadata); err != nil {
-			return nil, err
-		}
-
 		endpoint := fmt.Sprintf("/services/%s/options", id)
 		if err := s.Client.Put(ctx, endpoint, options, &updated); err != nil {
 			return nil, err
@@ -182,6 +189,18 @@ func (s *ServiceOptionsService) UpdateOptions(ctx context.Context, id string, op
 	return updated, nil
 }
 
+// splitProtectServe returns a copy of options without protectServeKeyEnabled,
+// which is applied through the ProtectServe key endpoints, and its value.
+func splitProtectServe(options ServiceOptions) (ServiceOptions, *bool) {
+	val, ok := options[OptionProtectServe].(bool)
+	if !ok {
+		return options, nil
+	}
+	rest := maps.Clone(options)
+	delete(rest, OptionProtectServe)
+	return rest, &val
+}
+
 // validateOptions performs strict validation against metadata
 func (s *ServiceOptionsService) validateOptions(options ServiceOptions, metadata *ServiceOptionsMetadata) error {
 	var validationErrors []ValidationError
@@ -193,24 +212,7 @@ func (s *ServiceOptionsService) validateOptions(options ServiceOptions, metadata
 		if opt.Type == "dynamic" && opt.Property != nil {
 			dynamicOptions[opt.Property.Name] = opt
 		} else if opt.Type == "standard" {
-			var optName string
-			switch opt.Name {
-			case "Reverse Proxy":
-				optName = "reverseProxy"
-			case "ProtectServe":
-				optName = "protectServeKeyEnabled"
-			case "CORS Override":
-				optName = "cors"
-			case "Expiry Overrides":
-				optName = "expiryHeaders"
-			case "Referrer Blocking":
-				optName = "referrerBlocking"
-			case "Auto HTTPS Redirect":
-				optName = "autoRedirect"
-			default:
-				optName = opt.Name
-			}
-			standardOptions[optName] = opt
+			standardOptions[OptionKey(opt)] = opt
 		}
 	}
 
@@ -549,32 +551,8 @@ func (s *ServiceOptionsService) IsOptionAvailable(ctx context.Context, id string
 		return false, nil, err
 	}
 
-	// Check both dynamic and standard options
-	for _, opt := range metadata.Data {
-		if opt.Type == "dynamic" && opt.Property != nil && opt.Property.Name == optionName {
-			return true, &opt, nil
-		} else if opt.Type == "standard" {
-			var mappedName string
-			switch opt.Name {
-			case "Reverse Proxy":
-				mappedName = "reverseProxy"
-			case "ProtectServe":
-				mappedName = "protectServeKeyEnabled"
-			case "CORS Override":
-				mappedName = "cors"
-			case "Expiry Overrides":
-				mappedName = "expiryHeaders"
-			case "Referrer Blocking":
-				mappedName = "referrerBlocking"
-			case "Auto HTTPS Redirect":
-				mappedName = "autoRedirect"
-			default:
-				mappedName = opt.Name
-			}
-			if mappedName == optionName {
-				return true, &opt, nil
-			}
-		}
+	if opt, ok := NewOptionRegistry(metadata).Lookup(optionName); ok {
+		return true, &opt, nil
 	}
 	return false, nil, nil
 }
@@ -586,32 +564,7 @@ func (s *ServiceOptionsService) GetAvailableOptionNames(ctx context.Context, id
 		return nil, err
 	}
 
-	var names []string
-	for _, opt := range metadata.Data {
-		if opt.Type == "dynamic" && opt.Property != nil {
-			names = append(names, opt.Property.Name)
-		} else if opt.Type == "standard" {
-			var mappedName string
-			switch opt.Name {
-			case "Reverse Proxy":
-				mappedName = "reverseProxy"
-			case "ProtectServe":
-				mappedName = "protectServeKeyEnabled"
-			case "CORS Override":
-				mappedName = "cors"
-			case "Expiry Overrides":
-				mappedName = "expiryHeaders"
-			case "Referrer Blocking":
-				mappedName = "referrerBlocking"
-			case "Auto HTTPS Redirect":
-				mappedName = "autoRedirect"
-			default:
-				mappedName = opt.Name
-			}
-			names = append(names, mappedName)
-		}
-	}
-	return names, nil
+	return NewOptionRegistry(metadata).Keys(), nil
 }
 
 // GetOptionsByGroup returns options grouped by their group field
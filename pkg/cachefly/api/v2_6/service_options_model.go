@@ -0,0 +1,475 @@
+package v2_6
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Keys of the standard options in the options document.
+const (
+	OptionReverseProxy     = "reverseProxy"
+	OptionProtectServe     = "protectServeKeyEnabled"
+	OptionCORS             = "cors"
+	OptionExpiryHeaders    = "expiryHeaders"
+	OptionReferrerBlocking = "referrerBlocking"
+	OptionAutoRedirect     = "autoRedirect"
+)
+
+// standardOptionKeys maps the metadata names of standard options to their
+// keys in the options document.
+var standardOptionKeys = map[string]string{
+	"Reverse Proxy":       OptionReverseProxy,
+	"ProtectServe":        OptionProtectServe,
+	"CORS Override":       OptionCORS,
+	"Expiry Overrides":    OptionExpiryHeaders,
+	"Referrer Blocking":   OptionReferrerBlocking,
+	"Auto HTTPS Redirect": OptionAutoRedirect,
+}
+
+// OptionKey returns the key under which an option described by the metadata
+// appears in the options document.
+func OptionKey(opt OptionMetadata) string {
+	if opt.Type == "dynamic" && opt.Property != nil {
+		return opt.Property.Name
+	}
+	if key, ok := standardOptionKeys[opt.Name]; ok {
+		return key
+	}
+	return opt.Name
+}
+
+// OptionRegistry indexes the option metadata of a service by options
+// document key. It is built from the API's metadata, so options added to the
+// platform are available without an SDK release.
+type OptionRegistry struct {
+	keys  []string
+	byKey map[string]OptionMetadata
+}
+
+// NewOptionRegistry builds a registry from the metadata returned by
+// GetOptionsMetadata. Entries that are neither standard nor dynamic options
+// with a property are skipped.
+func NewOptionRegistry(metadata *ServiceOptionsMetadata) *OptionRegistry {
+	r := &OptionRegistry{byKey: map[string]OptionMetadata{}}
+	if metadata == nil {
+		return r
+	}
+	for _, opt := range metadata.Data {
+		if !(opt.Type == "standard" || opt.Type == "dynamic" && opt.Property != nil) {
+			continue
+		}
+		key := OptionKey(opt)
+		if _, dup := r.byKey[key]; !dup {
+			r.keys = append(r.keys, key)
+		}
+		r.byKey[key] = opt
+	}
+	return r
+}
+
+// Registry fetches the option metadata of a service and indexes it.
+func (s *ServiceOptionsService) Registry(ctx context.Context, id string) (*OptionRegistry, error) {
+	metadata, err := s.GetOptionsMetadata(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return NewOptionRegistry(metadata), nil
+}
+
+// Lookup returns the metadata of the option stored under key.
+func (r *OptionRegistry) Lookup(key string) (OptionMetadata, bool) {
+	opt, ok := r.byKey[key]
+	return opt, ok
+}
+
+// Keys returns the keys of every available option in metadata order.
+func (r *OptionRegistry) Keys() []string {
+	return slices.Clone(r.keys)
+}
+
+// IsDynamic reports whether key is a dynamic option.
+func (r *OptionRegistry) IsDynamic(key string) bool {
+	opt, ok := r.byKey[key]
+	return ok && opt.Type == "dynamic"
+}
+
+// Dynamic returns the metadata of every dynamic option in metadata order.
+func (r *OptionRegistry) Dynamic() []OptionMetadata {
+	var out []OptionMetadata
+	for _, key := range r.keys {
+		if opt := r.byKey[key]; opt.Type == "dynamic" {
+			out = append(out, opt)
+		}
+	}
+	return out
+}
+
+// Default returns the default value of a dynamic option, if it has one.
+func (r *OptionRegistry) Default(key string) (interface{}, bool) {
+	opt, ok := r.byKey[key]
+	if !ok || opt.Property == nil || opt.Property.Default == nil {
+		return nil, false
+	}
+	return opt.Property.Default, true
+}
+
+// ReverseProxy configures the reverse proxy standard option.
+type ReverseProxy struct {
+	Enabled           bool   `json:"enabled"`
+	Mode              string `json:"mode,omitempty"` // "WEB" or "OBJECT_STORAGE"
+	Hostname          string `json:"hostname,omitempty"`
+	OriginScheme      string `json:"originScheme,omitempty"` // "FOLLOW", "HTTP" or "HTTPS"
+	TTL               int    `json:"ttl,omitempty"`
+	CacheByQueryParam bool   `json:"cacheByQueryParam"`
+	UseRobotsTxt      bool   `json:"useRobotsTxt"`
+	AccessKey         string `json:"accessKey,omitempty"`
+	SecretKey         string `json:"secretKey,omitempty"`
+	Region            string `json:"region,omitempty"`
+
+	raw map[string]interface{}
+}
+
+func (o ReverseProxy) MarshalJSON() ([]byte, error) {
+	type plain ReverseProxy
+	return encodeOption(plain(o), o.raw)
+}
+
+func (o *ReverseProxy) UnmarshalJSON(data []byte) error {
+	type plain ReverseProxy
+	return decodeOption(data, (*plain)(o), &o.raw)
+}
+
+// ExpiryHeader overrides the cache expiry of a path or file extension.
+type ExpiryHeader struct {
+	Path       string `json:"path,omitempty"`
+	Extension  string `json:"extension,omitempty"`
+	ExpiryTime int    `json:"expiryTime"`
+
+	raw map[string]interface{}
+}
+
+func (o ExpiryHeader) MarshalJSON() ([]byte, error) {
+	type plain ExpiryHeader
+	return encodeOption(plain(o), o.raw)
+}
+
+func (o *ExpiryHeader) UnmarshalJSON(data []byte) error {
+	type plain ExpiryHeader
+	return decodeOption(data, (*plain)(o), &o.raw)
+}
+
+// ExpiryHeaders is the expiry overrides standard option.
+type ExpiryHeaders []ExpiryHeader
+
+// Toggle is an on/off option encoded as a JSON boolean.
+type Toggle struct {
+	Enabled bool
+}
+
+func (t Toggle) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Enabled)
+}
+
+// UnmarshalJSON accepts a boolean or an {"enabled": ...} object.
+func (t *Toggle) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &t.Enabled); err == nil {
+		return nil
+	}
+	var obj struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("expected a boolean, got %s", data)
+	}
+	t.Enabled = obj.Enabled
+	return nil
+}
+
+// CORS is the CORS override standard option.
+type CORS = Toggle
+
+// ReferrerBlocking is the referrer blocking standard option.
+type ReferrerBlocking = Toggle
+
+// AutoRedirect is the automatic HTTPS redirect standard option.
+type AutoRedirect = Toggle
+
+// DynamicOption is the value of a dynamic option. Options with an on/off
+// switch are encoded as {"enabled": ..., "value": ...}; the others as the
+// plain value, such as true or 3600.
+type DynamicOption struct {
+	// Switched reports whether the option uses the enabled/value form.
+	Switched bool
+	// Enabled is the switch of an option in the enabled/value form.
+	Enabled bool
+	// Value is the option value as decoded from JSON.
+	Value interface{}
+}
+
+// Switch returns a dynamic option in the enabled/value form.
+func Switch(enabled bool, value interface{}) DynamicOption {
+	return DynamicOption{Switched: true, Enabled: enabled, Value: value}
+}
+
+func (o DynamicOption) MarshalJSON() ([]byte, error) {
+	if !o.Switched {
+		return json.Marshal(o.Value)
+	}
+	obj := map[string]interface{}{"enabled": o.Enabled}
+	if o.Value != nil {
+		obj["value"] = o.Value
+	}
+	return json.Marshal(obj)
+}
+
+func (o *DynamicOption) UnmarshalJSON(data []byte) error {
+	*o = DynamicOption{}
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(data, &obj) != nil || !isSwitch(obj) {
+		return json.Unmarshal(data, &o.Value)
+	}
+	o.Switched = true
+	if err := json.Unmarshal(obj["enabled"], &o.Enabled); err != nil {
+		return fmt.Errorf("enabled must be a boolean: %w", err)
+	}
+	if v, ok := obj["value"]; ok {
+		return json.Unmarshal(v, &o.Value)
+	}
+	return nil
+}
+
+// isSwitch reports whether obj is in the {"enabled": ..., "value": ...} form.
+func isSwitch(obj map[string]json.RawMessage) bool {
+	if _, ok := obj["enabled"]; !ok {
+		return false
+	}
+	for k := range obj {
+		if k != "enabled" && k != "value" {
+			return false
+		}
+	}
+	return true
+}
+
+// TypedServiceOptions is a typed view of ServiceOptions. Nil fields are
+// absent from the options document, so a partially filled value can be used
+// as an update.
+type TypedServiceOptions struct {
+	ReverseProxy           *ReverseProxy
+	ProtectServeKeyEnabled *bool
+	CORS                   *CORS
+	ExpiryHeaders          *ExpiryHeaders
+	ReferrerBlocking       *ReferrerBlocking
+	AutoRedirect           *AutoRedirect
+
+	// Dynamic holds every other option by key.
+	Dynamic map[string]DynamicOption
+}
+
+// ParseServiceOptions converts an options document into its typed form.
+func ParseServiceOptions(opts ServiceOptions) (*TypedServiceOptions, error) {
+	t := &TypedServiceOptions{}
+	for key, value := range opts {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("option %q: %w", key, err)
+		}
+		var target interface{}
+		switch key {
+		case OptionReverseProxy:
+			t.ReverseProxy = &ReverseProxy{}
+			target = t.ReverseProxy
+		case OptionProtectServe:
+			t.ProtectServeKeyEnabled = new(bool)
+			target = t.ProtectServeKeyEnabled
+		case OptionCORS:
+			t.CORS = &CORS{}
+			target = t.CORS
+		case OptionExpiryHeaders:
+			t.ExpiryHeaders = &ExpiryHeaders{}
+			target = t.ExpiryHeaders
+		case OptionReferrerBlocking:
+			t.ReferrerBlocking = &ReferrerBlocking{}
+			target = t.ReferrerBlocking
+		case OptionAutoRedirect:
+			t.AutoRedirect = &AutoRedirect{}
+			target = t.AutoRedirect
+		default:
+			var d DynamicOption
+			if err := json.Unmarshal(data, &d); err != nil {
+				return nil, fmt.Errorf("option %q: %w", key, err)
+			}
+			if t.Dynamic == nil {
+				t.Dynamic = map[string]DynamicOption{}
+			}
+			t.Dynamic[key] = d
+			continue
+		}
+		if err := json.Unmarshal(data, target); err != nil {
+			return nil, fmt.Errorf("option %q: %w", key, err)
+		}
+	}
+	return t, nil
+}
+
+// ServiceOptions converts the typed options back into an options document.
+func (t *TypedServiceOptions) ServiceOptions() (ServiceOptions, error) {
+	opts := ServiceOptions{}
+	set := func(key string, value interface{}) error {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("option %q: %w", key, err)
+		}
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		opts[key] = v
+		return nil
+	}
+
+	standard := []struct {
+		key   string
+		set   bool
+		value interface{}
+	}{
+		{OptionReverseProxy, t.ReverseProxy != nil, t.ReverseProxy},
+		{OptionProtectServe, t.ProtectServeKeyEnabled != nil, t.ProtectServeKeyEnabled},
+		{OptionCORS, t.CORS != nil, t.CORS},
+		{OptionExpiryHeaders, t.ExpiryHeaders != nil, t.ExpiryHeaders},
+		{OptionReferrerBlocking, t.ReferrerBlocking != nil, t.ReferrerBlocking},
+		{OptionAutoRedirect, t.AutoRedirect != nil, t.AutoRedirect},
+	}
+	for _, o := range standard {
+		if o.set {
+			if err := set(o.key, o.value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(t.Dynamic)) {
+		if err := set(key, t.Dynamic[key]); err != nil {
+			return nil, err
+		}
+	}
+	return opts, nil
+}
+
+func (t TypedServiceOptions) MarshalJSON() ([]byte, error) {
+	opts, err := t.ServiceOptions()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(opts)
+}
+
+func (t *TypedServiceOptions) UnmarshalJSON(data []byte) error {
+	var opts ServiceOptions
+	if err := json.Unmarshal(data, &opts); err != nil {
+		return err
+	}
+	parsed, err := ParseServiceOptions(opts)
+	if err != nil {
+		return err
+	}
+	*t = *parsed
+	return nil
+}
+
+// GetTypedOptions retrieves the current options of a service in typed form.
+func (s *ServiceOptionsService) GetTypedOptions(ctx context.Context, id string) (*TypedServiceOptions, error) {
+	opts, err := s.GetOptions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return ParseServiceOptions(opts)
+}
+
+// UpdateTypedOptions updates the options set in opts and returns the
+// resulting options in typed form.
+func (s *ServiceOptionsService) UpdateTypedOptions(ctx context.Context, id string, opts *TypedServiceOptions) (*TypedServiceOptions, error) {
+	update, err := opts.ServiceOptions()
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.UpdateOptions(ctx, id, update)
+	if err != nil {
+		return nil, err
+	}
+	return ParseServiceOptions(updated)
+}
+
+// encodeOption encodes v on top of the fields it was decoded from, so fields
+// unknown to the SDK survive a round trip and zero fields that were absent
+// are not added. Fields that were present are always written, so setting one
+// to its zero value clears it.
+func encodeOption(v interface{}, raw map[string]interface{}) ([]byte, error) {
+	if raw == nil {
+		return json.Marshal(v)
+	}
+	known, err := optionFields(v)
+	if err != nil {
+		return nil, err
+	}
+	out := maps.Clone(raw)
+	for k, value := range known {
+		if _, present := raw[k]; present || !isZeroJSON(value) {
+			out[k] = value
+		}
+	}
+	return json.Marshal(out)
+}
+
+// optionFields returns the JSON value of every field of the struct v,
+// including zero fields tagged omitempty.
+func optionFields(v interface{}) (map[string]interface{}, error) {
+	rv := reflect.ValueOf(v)
+	fields := map[string]interface{}{}
+	for i := 0; i < rv.NumField(); i++ {
+		f := rv.Type().Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		data, err := json.Marshal(rv.Field(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		fields[name] = value
+	}
+	return fields, nil
+}
+
+// decodeOption decodes data into v and keeps every field in raw.
+func decodeOption(data []byte, v interface{}, raw *map[string]interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, raw)
+}
+
+func isZeroJSON(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == ""
+	}
+	return false
+}
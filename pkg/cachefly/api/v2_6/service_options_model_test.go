@@ -0,0 +1,144 @@
+package v2_6
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTypedServiceOptions_RoundTrip(t *testing.T) {
+	doc := `{
+		"reverseProxy": {"enabled": true, "mode": "WEB", "hostname": "origin.example.com", "ttl": 3600, "cacheByQueryParam": false, "originScheme": "HTTPS", "useRobotsTxt": true, "prependPath": "/v2"},
+		"cors": true,
+		"autoRedirect": false,
+		"expiryHeaders": [{"path": "/img", "expiryTime": 86400, "note": "kept"}],
+		"protectServeKeyEnabled": true,
+		"error_ttl": {"enabled": true, "value": 60},
+		"maxcons": {"enabled": false},
+		"brotli_support": true,
+		"httpmethods": {"enabled": true, "value": {"GET": true, "POST": false}},
+		"originhostheader": ["a.example.com"]
+	}`
+
+	var typed TypedServiceOptions
+	if err := json.Unmarshal([]byte(doc), &typed); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if typed.ReverseProxy == nil || typed.ReverseProxy.Hostname != "origin.example.com" || typed.ReverseProxy.TTL != 3600 {
+		t.Errorf("Unexpected reverse proxy %+v", typed.ReverseProxy)
+	}
+	if typed.CORS == nil || !typed.CORS.Enabled || typed.AutoRedirect == nil || typed.AutoRedirect.Enabled {
+		t.Errorf("Unexpected toggles cors=%v autoRedirect=%v", typed.CORS, typed.AutoRedirect)
+	}
+	if typed.ExpiryHeaders == nil || (*typed.ExpiryHeaders)[0].ExpiryTime != 86400 {
+		t.Errorf("Unexpected expiry headers %+v", typed.ExpiryHeaders)
+	}
+	if ttl := typed.Dynamic["error_ttl"]; !ttl.Switched || !ttl.Enabled || ttl.Value != float64(60) {
+		t.Errorf("Unexpected error_ttl %+v", ttl)
+	}
+	if b := typed.Dynamic["brotli_support"]; b.Switched || b.Value != true {
+		t.Errorf("Unexpected brotli_support %+v", b)
+	}
+	if typed.ReferrerBlocking != nil {
+		t.Errorf("Expected absent options to stay nil")
+	}
+
+	out, err := json.Marshal(typed)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var want, got map[string]interface{}
+	json.Unmarshal([]byte(doc), &want)
+	json.Unmarshal(out, &got)
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Round trip changed the options:\nwant %v\ngot  %v", want, got)
+	}
+}
+
+func TestReverseProxy_ClearField(t *testing.T) {
+	var rp ReverseProxy
+	if err := json.Unmarshal([]byte(`{"enabled":true,"hostname":"origin.example.com","secretKey":"s3cret","ttl":60,"prependPath":"/v2"}`), &rp); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	rp.SecretKey = ""
+	rp.Hostname = ""
+	rp.TTL = 0
+
+	out, err := json.Marshal(rp)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var got map[string]interface{}
+	json.Unmarshal(out, &got)
+	want := map[string]interface{}{
+		"enabled": true, "hostname": "", "secretKey": "", "ttl": float64(0), "prependPath": "/v2",
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Expected cleared fields to be sent:\nwant %v\ngot  %v", want, got)
+	}
+}
+
+func TestTypedServiceOptions_Update(t *testing.T) {
+	enabled := true
+	typed := TypedServiceOptions{
+		ReverseProxy:           &ReverseProxy{Enabled: true, Mode: "WEB", Hostname: "o.example.com", OriginScheme: "FOLLOW", TTL: 60},
+		ProtectServeKeyEnabled: &enabled,
+		CORS:                   &CORS{Enabled: true},
+		Dynamic:                map[string]DynamicOption{"error_ttl": Switch(true, 30), "sendxff": {Value: true}},
+	}
+	opts, err := typed.ServiceOptions()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	rp := opts["reverseProxy"].(map[string]interface{})
+	if rp["hostname"] != "o.example.com" || rp["cacheByQueryParam"] != false || rp["useRobotsTxt"] != false {
+		t.Errorf("Expected reverse proxy with its required fields, got %v", rp)
+	}
+	if opts["cors"] != true || opts["sendxff"] != true {
+		t.Errorf("Unexpected options %v", opts)
+	}
+	if ttl := opts["error_ttl"].(map[string]interface{}); ttl["enabled"] != true || ttl["value"] != float64(30) {
+		t.Errorf("Unexpected error_ttl %v", ttl)
+	}
+	if _, ok := opts["expiryHeaders"]; ok {
+		t.Errorf("Expected unset options to be left out, got %v", opts)
+	}
+
+	svc := &ServiceOptionsService{}
+	if err := svc.validateOptions(opts, &ServiceOptionsMetadata{Data: []OptionMetadata{
+		{Name: "Reverse Proxy", Type: "standard"},
+		{Name: "ProtectServe", Type: "standard"},
+		{Name: "CORS Override", Type: "standard"},
+		{Name: "Error TTL", Type: "dynamic", Property: &OptionProperty{Name: "error_ttl", Type: "integer"}},
+		{Name: "XFF", Type: "dynamic", Property: &OptionProperty{Name: "sendxff", Type: "boolean"}},
+	}}); err != nil {
+		t.Errorf("Expected typed options to pass validation, got %v", err)
+	}
+}
+
+func TestOptionRegistry(t *testing.T) {
+	registry := NewOptionRegistry(&ServiceOptionsMetadata{Data: []OptionMetadata{
+		{Name: "CORS Override", Type: "standard"},
+		{Name: "Auto HTTPS Redirect", Type: "standard"},
+		{Name: "Brand New", Type: "dynamic", Property: &OptionProperty{Name: "brand_new", Type: "integer", Default: float64(5)}},
+		{Name: "Broken", Type: "dynamic"},
+	}})
+
+	if keys := registry.Keys(); !reflect.DeepEqual(keys, []string{"cors", "autoRedirect", "brand_new"}) {
+		t.Errorf("Unexpected keys %v", keys)
+	}
+	if opt, ok := registry.Lookup("brand_new"); !ok || opt.Name != "Brand New" || !registry.IsDynamic("brand_new") {
+		t.Errorf("Expected dynamic option to be discovered, got %+v", opt)
+	}
+	if registry.IsDynamic("cors") {
+		t.Errorf("Expected cors to be a standard option")
+	}
+	if def, ok := registry.Default("brand_new"); !ok || def != float64(5) {
+		t.Errorf("Unexpected default %v", def)
+	}
+	if len(registry.Dynamic()) != 1 {
+		t.Errorf("Expected one dynamic option, got %v", registry.Dynamic())
+	}
+}
@@ -0,0 +1,206 @@
+package v2_6
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/cachefly/cachefly-sdk-go/internal/httpclient"
+)
+
+// OptionChangeKind tells how an option field changes.
+type OptionChangeKind string
+
+const (
+	OptionAdded   OptionChangeKind = "added"
+	OptionChanged OptionChangeKind = "changed"
+	OptionRemoved OptionChangeKind = "removed"
+)
+
+// OptionChange is a single field-level change of a service option.
+type OptionChange struct {
+	// Path is the option key, followed by the field names for changes inside
+	// object options, e.g. "reverseProxy.hostname".
+	Path string
+	Kind OptionChangeKind
+	// Old and New are nil for added and removed fields respectively.
+	Old interface{}
+	New interface{}
+}
+
+func (c OptionChange) String() string {
+	switch c.Kind {
+	case OptionAdded:
+		return fmt.Sprintf("+ %s = %s", c.Path, formatOptionValue(c.New))
+	case OptionRemoved:
+		return fmt.Sprintf("- %s (was %s)", c.Path, formatOptionValue(c.Old))
+	default:
+		return fmt.Sprintf("~ %s: %s => %s", c.Path, formatOptionValue(c.Old), formatOptionValue(c.New))
+	}
+}
+
+// OptionsPlan lists the changes an options update would make to a service.
+type OptionsPlan struct {
+	ServiceID string
+	// Current holds the options of the service when the plan was made.
+	Current ServiceOptions
+	// Update holds the options to send, as passed to PlanOptions.
+	Update  ServiceOptions
+	Changes []OptionChange
+}
+
+// Empty reports whether applying the plan would change nothing.
+func (p *OptionsPlan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String renders the plan as one change per line.
+func (p *OptionsPlan) String() string {
+	if p.Empty() {
+		return "No changes.\n"
+	}
+	var b strings.Builder
+	for _, c := range p.Changes {
+		b.WriteString(c.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// PlanOptions is the dry run of UpdateOptions: it fetches the current options,
+// validates options against the metadata exactly as UpdateOptions does, and
+// returns the field-level changes the update would make without sending it.
+//
+// Object options are replaced as a whole by an update, so fields missing from
+// the new object are reported as removed.
+//
+// protectServeKeyEnabled is not part of the options document; it is compared
+// with whether the service has a ProtectServe key. Since setting it to true
+// regenerates an existing key, that is reported as a change of
+// protectServeKey.
+func (s *ServiceOptionsService) PlanOptions(ctx context.Context, id string, options ServiceOptions) (*OptionsPlan, error) {
+	if id == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+
+	current, err := s.GetOptions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateUpdate(ctx, id, options); err != nil {
+		return nil, err
+	}
+
+	rest, protectServeKeyEnabled := splitProtectServe(options)
+	update, err := normalizeOptions(rest)
+	if err != nil {
+		return nil, err
+	}
+	plan := &OptionsPlan{ServiceID: id, Current: current, Update: maps.Clone(options)}
+	for _, key := range slices.Sorted(maps.Keys(update)) {
+		old, exists := current[key]
+		plan.Changes = append(plan.Changes, diffValue(key, old, exists, update[key])...)
+	}
+
+	if protectServeKeyEnabled != nil {
+		change, err := s.planProtectServe(ctx, id, *protectServeKeyEnabled)
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			plan.Changes = append(plan.Changes, *change)
+			slices.SortStableFunc(plan.Changes, func(a, b OptionChange) int {
+				return strings.Compare(strings.SplitN(a.Path, ".", 2)[0], strings.SplitN(b.Path, ".", 2)[0])
+			})
+		}
+	}
+	return plan, nil
+}
+
+// planProtectServe compares protectServeKeyEnabled with whether the service
+// has a ProtectServe key. Enabling it regenerates an existing key, which is
+// reported as a change of protectServeKey.
+func (s *ServiceOptionsService) planProtectServe(ctx context.Context, id string, enabled bool) (*OptionChange, error) {
+	hasKey := true
+	key, err := s.GetProtectServeKey(ctx, id, true)
+	switch {
+	case errors.Is(err, httpclient.ErrNotFound):
+		hasKey = false
+	case err != nil:
+		return nil, fmt.Errorf("failed to get ProtectServe key: %w", err)
+	default:
+		hasKey = key.ProtectServeKey != ""
+	}
+
+	switch {
+	case enabled && hasKey:
+		return &OptionChange{Path: "protectServeKey", Kind: OptionChanged, Old: "(current key)", New: "(regenerated)"}, nil
+	case enabled != hasKey:
+		return &OptionChange{Path: OptionProtectServe, Kind: OptionChanged, Old: hasKey, New: enabled}, nil
+	}
+	return nil, nil
+}
+
+// diffValue compares two option values, descending into objects.
+func diffValue(path string, old interface{}, exists bool, value interface{}) []OptionChange {
+	if !exists {
+		return []OptionChange{{Path: path, Kind: OptionAdded, New: value}}
+	}
+
+	oldObj, oldIsObj := old.(map[string]interface{})
+	newObj, newIsObj := value.(map[string]interface{})
+	if !oldIsObj || !newIsObj {
+		if reflect.DeepEqual(old, value) {
+			return nil
+		}
+		return []OptionChange{{Path: path, Kind: OptionChanged, Old: old, New: value}}
+	}
+
+	keys := slices.Collect(maps.Keys(oldObj))
+	for k := range newObj {
+		if _, ok := oldObj[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	var changes []OptionChange
+	for _, k := range keys {
+		o, inOld := oldObj[k]
+		n, inNew := newObj[k]
+		if !inNew {
+			changes = append(changes, OptionChange{Path: path + "." + k, Kind: OptionRemoved, Old: o})
+			continue
+		}
+		changes = append(changes, diffValue(path+"."+k, o, inOld, n)...)
+	}
+	return changes
+}
+
+// normalizeOptions converts options to the form decoded from API responses,
+// so that for example 60 and 60.0 compare equal.
+func normalizeOptions(options ServiceOptions) (ServiceOptions, error) {
+	data, err := json.Marshal(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode options: %w", err)
+	}
+	var out ServiceOptions
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func formatOptionValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
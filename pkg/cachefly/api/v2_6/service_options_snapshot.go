@@ -0,0 +1,67 @@
+package v2_6
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// SaveOptionsMetadata writes metadata to a snapshot file in the format returned
+// by the options metadata endpoint, so that ValidateOptions can check options
+// without API access, e.g. in CI.
+func SaveOptionsMetadata(path string, metadata *ServiceOptionsMetadata) error {
+	if metadata == nil {
+		return fmt.Errorf("metadata is required")
+	}
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode options metadata: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// LoadOptionsMetadata reads a snapshot written by SaveOptionsMetadata or saved
+// from the options metadata endpoint.
+func LoadOptionsMetadata(path string) (*ServiceOptionsMetadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var metadata ServiceOptionsMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to decode options metadata %s: %w", path, err)
+	}
+	if len(metadata.Data) == 0 {
+		return nil, fmt.Errorf("options metadata %s has no options", path)
+	}
+	return &metadata, nil
+}
+
+// ValidateOptions checks options against metadata without calling the API,
+// applying the same rules as UpdateOptions. It returns a
+// ServiceOptionsValidationError listing every invalid option.
+func ValidateOptions(options ServiceOptions, metadata *ServiceOptionsMetadata) error {
+	if metadata == nil {
+		return fmt.Errorf("metadata is required")
+	}
+	rest, _ := splitProtectServe(options)
+	if len(rest) == 0 {
+		return nil
+	}
+	var s ServiceOptionsService
+	return s.validateOptions(rest, metadata)
+}
+
+// UpdateOptionsWithMetadata is UpdateOptions validating against the given
+// metadata instead of fetching it first, which saves a request per service in
+// bulk updates.
+func (s *ServiceOptionsService) UpdateOptionsWithMetadata(ctx context.Context, id string, options ServiceOptions, metadata *ServiceOptionsMetadata) (ServiceOptions, error) {
+	if id == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+	if err := ValidateOptions(options, metadata); err != nil {
+		return nil, err
+	}
+	return s.updateOptions(ctx, id, options)
+}
@@ -0,0 +1,104 @@
+package v2_6
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/cachefly/cachefly-sdk-go/internal/httpclient"
+)
+
+func snapshotMetadata() *ServiceOptionsMetadata {
+	min, max := 0, 3600
+	return &ServiceOptionsMetadata{Data: []OptionMetadata{
+		{Name: "CORS Override", Type: "standard"},
+		{Name: "Error TTL", Type: "dynamic", Property: &OptionProperty{Name: "error_ttl", Type: "integer", MinValue: &min, MaxValue: &max}},
+		{Name: "Edge to Origin", Type: "dynamic", ReadOnly: true, Property: &OptionProperty{Name: "edgetoorigin", Type: "boolean"}},
+	}}
+}
+
+func TestOptionsMetadataSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metadata.json")
+	if err := SaveOptionsMetadata(path, snapshotMetadata()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	metadata, err := LoadOptionsMetadata(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(metadata.Data) != 3 || metadata.Data[1].Property.Name != "error_ttl" || *metadata.Data[1].Property.MaxValue != 3600 {
+		t.Errorf("Unexpected metadata %+v", metadata.Data)
+	}
+
+	if err := ValidateOptions(ServiceOptions{"cors": true, "error_ttl": 60, "protectServeKeyEnabled": true}, metadata); err != nil {
+		t.Errorf("Expected valid options, got %v", err)
+	}
+
+	err = ValidateOptions(ServiceOptions{"cors": "yes", "error_ttl": 7200, "edgetoorigin": true, "unknown": 1}, metadata)
+	var verr ServiceOptionsValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected ServiceOptionsValidationError, got %v", err)
+	}
+	codes := map[string]string{}
+	for _, e := range verr.Errors {
+		codes[e.Field] = e.Code
+	}
+	want := map[string]string{"cors": "INVALID_VALUE", "error_ttl": "INVALID_VALUE", "edgetoorigin": "OPTION_READ_ONLY", "unknown": "OPTION_NOT_AVAILABLE"}
+	for field, code := range want {
+		if codes[field] != code {
+			t.Errorf("Expected %s for %s, got %q", code, field, codes[field])
+		}
+	}
+}
+
+func TestLoadOptionsMetadata_Invalid(t *testing.T) {
+	if _, err := LoadOptionsMetadata(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for a missing file")
+	}
+
+	path := filepath.Join(t.TempDir(), "empty.json")
+	if err := SaveOptionsMetadata(path, &ServiceOptionsMetadata{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := LoadOptionsMetadata(path); err == nil {
+		t.Error("Expected error for a snapshot without options")
+	}
+}
+
+func TestServiceOptionsService_UpdateOptionsWithMetadata(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	client := httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "test-token"})
+	svc := &ServiceOptionsService{Client: client}
+
+	updated, err := svc.UpdateOptionsWithMetadata(context.Background(), "svc-123", ServiceOptions{"error_ttl": 60}, snapshotMetadata())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated["error_ttl"] != float64(60) {
+		t.Errorf("Expected error_ttl 60, got %v", updated["error_ttl"])
+	}
+	if len(requests) != 1 || requests[0] != "PUT /api/2.6/services/svc-123/options" {
+		t.Errorf("Expected a single PUT without fetching metadata, got %v", requests)
+	}
+
+	_, err = svc.UpdateOptionsWithMetadata(context.Background(), "svc-123", ServiceOptions{"error_ttl": -1}, snapshotMetadata())
+	if _, ok := err.(ServiceOptionsValidationError); !ok {
+		t.Errorf("Expected ServiceOptionsValidationError, got %v", err)
+	}
+	if len(requests) != 1 {
+		t.Errorf("Expected invalid options not to be sent, got %v", requests)
+	}
+}
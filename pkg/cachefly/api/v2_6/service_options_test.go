@@ -296,3 +296,119 @@ func TestServiceOptionsService_ErrorHandling(t *testing.T) {
 		t.Errorf("Expected 'id is required' error for UpdateOptions, got %s", err.Error())
 	}
 }
+
+func TestServiceOptionsService_PlanOptions(t *testing.T) {
+	protectServeKey := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected a dry run to send no %s request", r.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/2.6/services/svc-123/options/protectserve":
+			if protectServeKey == "" {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"message":"not found"}`))
+				return
+			}
+			w.Write([]byte(`{"protectServeKey":"` + protectServeKey + `"}`))
+		case "/api/2.6/services/svc-123/options":
+			w.Write([]byte(`{"cors":false,"error_ttl":{"enabled":true,"value":60},"reverseProxy":{"enabled":true,"hostname":"old.example.com","ttl":60,"prependPath":"/v1"},"sendxff":true}`))
+		case "/api/2.6/services/svc-123/options/metadata":
+			w.Write([]byte(`{"meta":{"count":5},"data":[
+				{"name":"CORS Override","type":"standard"},
+				{"name":"Reverse Proxy","type":"standard"},
+				{"name":"Error TTL","type":"dynamic","property":{"name":"error_ttl","type":"integer","maxValue":3600}},
+				{"name":"XFF","type":"dynamic","property":{"name":"sendxff","type":"boolean"}},
+				{"name":"Brotli","type":"dynamic","property":{"name":"brotli_support","type":"boolean"}}
+			]}`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	svc := &ServiceOptionsService{Client: httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "test-token"})}
+
+	update := ServiceOptions{
+		"cors":                   true,
+		"error_ttl":              map[string]interface{}{"enabled": true, "value": 60},
+		"brotli_support":         true,
+		"protectServeKeyEnabled": true,
+		"reverseProxy": map[string]interface{}{
+			"enabled": true, "mode": "WEB", "hostname": "new.example.com", "originScheme": "HTTPS",
+			"ttl": 60, "useRobotsTxt": false, "cacheByQueryParam": false,
+		},
+	}
+	plan, err := svc.PlanOptions(context.Background(), "svc-123", update)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := `+ brotli_support = true
+~ cors: false => true
+~ protectServeKeyEnabled: false => true
++ reverseProxy.cacheByQueryParam = false
+~ reverseProxy.hostname: "old.example.com" => "new.example.com"
++ reverseProxy.mode = "WEB"
++ reverseProxy.originScheme = "HTTPS"
+- reverseProxy.prependPath (was "/v1")
++ reverseProxy.useRobotsTxt = false
+`
+	if plan.String() != want {
+		t.Errorf("Unexpected plan:\n%s\nwant:\n%s", plan, want)
+	}
+	if _, ok := update["protectServeKeyEnabled"]; !ok {
+		t.Errorf("Expected the caller's options to be left untouched")
+	}
+
+	_, err = svc.PlanOptions(context.Background(), "svc-123", ServiceOptions{"error_ttl": map[string]interface{}{"enabled": true, "value": 7200}})
+	if _, ok := err.(ServiceOptionsValidationError); !ok {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+
+	plan, err = svc.PlanOptions(context.Background(), "svc-123", ServiceOptions{"sendxff": true, "protectServeKeyEnabled": false})
+	if err != nil || !plan.Empty() {
+		t.Errorf("Expected an empty plan, got %v (%v)", plan, err)
+	}
+
+	protectServeKey = "****"
+	plan, err = svc.PlanOptions(context.Background(), "svc-123", ServiceOptions{"protectServeKeyEnabled": true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := "~ protectServeKey: \"(current key)\" => \"(regenerated)\"\n"; plan.String() != want {
+		t.Errorf("Expected key regeneration, got:\n%s", plan)
+	}
+	plan, err = svc.PlanOptions(context.Background(), "svc-123", ServiceOptions{"protectServeKeyEnabled": false})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := "~ protectServeKeyEnabled: true => false\n"; plan.String() != want {
+		t.Errorf("Expected key removal, got:\n%s", plan)
+	}
+}
+
+func TestServiceOptionsService_UpdateOptions_KeepsCallerMap(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/2.6/services/svc-123/options/metadata":
+			w.Write([]byte(`{"meta":{"count":1},"data":[{"name":"CORS Override","type":"standard"}]}`))
+		case r.URL.Path == "/api/2.6/services/svc-123/options/protectserve":
+			w.Write([]byte(`{"protectServeKey":"k"}`))
+		default:
+			w.Write([]byte(`{"cors":true}`))
+		}
+	}))
+	defer server.Close()
+
+	svc := &ServiceOptionsService{Client: httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "test-token"})}
+	options := ServiceOptions{"cors": true, "protectServeKeyEnabled": true}
+	if _, err := svc.UpdateOptions(context.Background(), "svc-123", options); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(options) != 2 || options["protectServeKeyEnabled"] != true {
+		t.Errorf("Expected the caller's options to be left untouched, got %v", options)
+	}
+}
@@ -3,6 +3,10 @@ package v2_6
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
 )
 
 // PurgeRequest represents a request to purge cached content for a service.
@@ -31,3 +35,201 @@ func (s *ServicesService) Purge(ctx context.Context, id string, req PurgeRequest
 	}
 	return nil
 }
+
+// DefaultPurgeBatchSize is the number of paths PurgeURLs sends per request
+// when PurgeOptions.BatchSize is not set.
+const DefaultPurgeBatchSize = 100
+
+// DefaultPurgeConcurrency is the number of purge requests PurgeURLs runs at
+// once when PurgeOptions.Concurrency is not set.
+const DefaultPurgeConcurrency = 4
+
+// PurgeOptions configures PurgeURLs.
+type PurgeOptions struct {
+	// BatchSize is the maximum number of paths per purge request.
+	BatchSize int
+	// Concurrency is the maximum number of purge requests in flight. Requests
+	// also respect the client's rate limiter and retry policy.
+	Concurrency int
+}
+
+// PurgeResult is the outcome of purging one of the inputs given to PurgeURLs.
+type PurgeResult struct {
+	// Input is the URL or path as passed to PurgeURLs.
+	Input string
+	// Path is the normalized path sent to the API; empty if Input was invalid.
+	Path string
+	// Batch is the index of the request that carried Path, or -1 if none did.
+	Batch int
+	// Err is nil when the path was purged.
+	Err error
+}
+
+// PurgeReport lists the outcome of every input of PurgeURLs, in input order.
+type PurgeReport struct {
+	Results []PurgeResult
+	// Batches is the number of purge requests sent.
+	Batches int
+}
+
+// Purged returns the number of inputs that were purged.
+func (r *PurgeReport) Purged() int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Err == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed returns the results of the inputs that were not purged.
+func (r *PurgeReport) Failed() []PurgeResult {
+	var failed []PurgeResult
+	for _, res := range r.Results {
+		if res.Err != nil {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Err summarizes the failures of the report, or returns nil if every input
+// was purged. The first failure is wrapped.
+func (r *PurgeReport) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d paths not purged, first %q: %w", len(failed), len(r.Results), failed[0].Input, failed[0].Err)
+}
+
+// PurgeURLs purges a list of full URLs or paths from a service's cache.
+//
+// Paths get a leading slash if missing; URLs must be http or https URLs on
+// one of the service's domains and are reduced to their path and query.
+// Duplicates are purged once, and the unique paths are sent in batches of
+// opts.BatchSize, opts.Concurrency at a time. The returned error is only set
+// when the purge could not start; the outcome of each input is in the report.
+func (s *ServicesService) PurgeURLs(ctx context.Context, id string, targets []string, opts PurgeOptions) (*PurgeReport, error) {
+	if id == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultPurgeBatchSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultPurgeConcurrency
+	}
+
+	var domains []string
+	if slices.ContainsFunc(targets, isPurgeURL) {
+		all, err := ListAll((&ServiceDomainsService{Client: s.Client}).All(ctx, id, ListServiceDomainsOptions{}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list service domains: %w", err)
+		}
+		for _, d := range all {
+			domains = append(domains, strings.ToLower(d.Name))
+		}
+	}
+
+	report := &PurgeReport{Results: make([]PurgeResult, len(targets))}
+	var paths []string
+	index := map[string]int{} // path => position in paths
+	for i, target := range targets {
+		res := PurgeResult{Input: target, Batch: -1}
+		res.Path, res.Err = normalizePurgeTarget(target, domains)
+		if res.Err == nil {
+			if _, ok := index[res.Path]; !ok {
+				index[res.Path] = len(paths)
+				paths = append(paths, res.Path)
+			}
+		}
+		report.Results[i] = res
+	}
+
+	batches := slices.Collect(slices.Chunk(paths, opts.BatchSize))
+	report.Batches = len(batches)
+	errs := make([]error, len(batches))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, opts.Concurrency)
+	for b, batch := range batches {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[b] = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[b] = s.Purge(ctx, id, PurgeRequest{Paths: batch})
+		}()
+	}
+	wg.Wait()
+
+	for i, res := range report.Results {
+		if res.Err != nil {
+			continue
+		}
+		b := index[res.Path] / opts.BatchSize
+		report.Results[i].Batch = b
+		report.Results[i].Err = errs[b]
+	}
+	return report, nil
+}
+
+// isPurgeURL reports whether target is an absolute URL rather than a path.
+func isPurgeURL(target string) bool {
+	u, err := url.Parse(strings.TrimSpace(target))
+	return err == nil && u.IsAbs()
+}
+
+// normalizePurgeTarget turns a URL on one of domains, or a path, into the
+// path to purge.
+func normalizePurgeTarget(target string, domains []string) (string, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return "", fmt.Errorf("empty path")
+	}
+	if !isPurgeURL(target) {
+		target, _, _ = strings.Cut(target, "#")
+		if !strings.HasPrefix(target, "/") {
+			target = "/" + target
+		}
+		return target, nil
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("unsupported URL scheme %q", u.Scheme)
+	}
+	host := strings.ToLower(u.Hostname())
+	if !slices.ContainsFunc(domains, func(d string) bool { return matchDomain(d, host) }) {
+		return "", fmt.Errorf("host %q is not a domain of the service", host)
+	}
+
+	p := u.EscapedPath()
+	if p == "" {
+		p = "/"
+	}
+	if u.RawQuery != "" {
+		p += "?" + u.RawQuery
+	}
+	return p, nil
+}
+
+// matchDomain reports whether host is domain or, for a wildcard domain such
+// as *.example.com, a direct subdomain of it.
+func matchDomain(domain, host string) bool {
+	if rest, ok := strings.CutPrefix(domain, "*."); ok {
+		sub, parent, found := strings.Cut(host, ".")
+		return found && sub != "" && parent == rest
+	}
+	return domain == host
+}
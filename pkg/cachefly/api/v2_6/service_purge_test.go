@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"sync"
 	"testing"
 
 	"github.com/cachefly/cachefly-sdk-go/internal/httpclient"
@@ -94,3 +96,88 @@ func TestServicesService_Purge_InvalidRequest(t *testing.T) {
 		t.Errorf("expected error for missing all/paths")
 	}
 }
+
+func TestServicesService_PurgeURLs(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/2.6/services/svc-123/domains":
+			w.Write([]byte(`{"meta":{"count":2},"data":[{"_id":"d1","name":"cdn.example.com"},{"_id":"d2","name":"*.assets.example.com"}]}`))
+		case "/api/2.6/services/svc-123/purge":
+			var req PurgeRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			mu.Lock()
+			batches = append(batches, req.Paths)
+			mu.Unlock()
+			if slices.Contains(req.Paths, "/fail") {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"message":"purge failed"}`))
+				return
+			}
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	svc := &ServicesService{Client: httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "test-token"})}
+
+	targets := []string{
+		"https://cdn.example.com/a.css",
+		"/a.css",
+		"b.js",
+		"http://img.assets.example.com/logo.png?v=2",
+		"https://other.example.com/x",
+		"/fail",
+		"",
+	}
+	report, err := svc.PurgeURLs(context.Background(), "svc-123", targets, PurgeOptions{BatchSize: 2, Concurrency: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if report.Batches != 2 || len(batches) != 2 {
+		t.Errorf("Expected 4 unique paths in 2 batches, got %d batches: %v", report.Batches, batches)
+	}
+	wantPaths := []string{"/a.css", "/a.css", "/b.js", "/logo.png?v=2", "", "/fail", ""}
+	for i, res := range report.Results {
+		if res.Path != wantPaths[i] {
+			t.Errorf("Result %d: expected path %q, got %q", i, wantPaths[i], res.Path)
+		}
+	}
+	if report.Results[1].Err != nil || report.Results[2].Err != nil {
+		t.Errorf("Expected the first batch to succeed, got %+v", report.Results[:3])
+	}
+	if report.Results[3].Err == nil || report.Results[5].Err == nil {
+		t.Errorf("Expected the failed batch to fail both of its paths, got %+v", report.Results)
+	}
+	if report.Results[4].Err == nil || report.Results[4].Batch != -1 {
+		t.Errorf("Expected a host outside the service to be rejected, got %+v", report.Results[4])
+	}
+	if report.Purged() != 3 || len(report.Failed()) != 4 || report.Err() == nil {
+		t.Errorf("Unexpected summary: %d purged, %d failed", report.Purged(), len(report.Failed()))
+	}
+}
+
+func TestNormalizePurgeTarget(t *testing.T) {
+	domains := []string{"cdn.example.com"}
+	tests := map[string]string{
+		"index.html":                           "/index.html",
+		" /images/ ":                           "/images/",
+		"/page#section":                        "/page",
+		"/r?to=https://x":                      "/r?to=https://x",
+		"https://CDN.example.com":              "/",
+		"https://cdn.example.com:443/a%20b.js": "/a%20b.js",
+	}
+	for in, want := range tests {
+		got, err := normalizePurgeTarget(in, domains)
+		if err != nil || got != want {
+			t.Errorf("normalizePurgeTarget(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := normalizePurgeTarget("ftp://cdn.example.com/x", domains); err == nil {
+		t.Errorf("Expected unsupported scheme to be rejected")
+	}
+}
@@ -3,6 +3,7 @@ package v2_6
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 
@@ -78,6 +79,18 @@ func (s *ServiceOptionsRefererRulesService) List(ctx context.Context, sid string
 	return &resp, nil
 }
 
+// All iterates over all referer rules of a service, fetching opts.Limit items per page.
+func (s *ServiceOptionsRefererRulesService) All(ctx context.Context, sid string, opts ListRefererRulesOptions) iter.Seq2[RefererRule, error] {
+	return paginate(ctx, opts.Offset, opts.Limit, func(ctx context.Context, offset, limit int) ([]RefererRule, MetaInfo, error) {
+		opts.Offset, opts.Limit = offset, limit
+		resp, err := s.List(ctx, sid, opts)
+		if err != nil {
+			return nil, MetaInfo{}, err
+		}
+		return resp.Rules, resp.Meta, nil
+	})
+}
+
 // Create adds a new referer rule to a service.
 func (s *ServiceOptionsRefererRulesService) Create(ctx context.Context, sid string, req CreateRefererRuleRequest) (*RefererRule, error) {
 	if sid == "" {
@@ -2,18 +2,57 @@ package v2_6
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 
 	"github.com/cachefly/cachefly-sdk-go/internal/httpclient"
 )
 
-// ServiceRule represents a rule configuration for a service.
+// ServiceRule represents a rule configuration for a service. The rule body
+// varies with the rule type; every field other than the identifiers and
+// timestamps is kept in Fields.
 type ServiceRule struct {
 	ID        string `json:"_id"`
 	CreatedAt string `json:"createdAt"`
 	UpdatedAt string `json:"updateAt"`
+
+	Fields map[string]interface{} `json:"-"`
+}
+
+// UnmarshalJSON decodes the known fields and collects the rest into Fields.
+func (r *ServiceRule) UnmarshalJSON(b []byte) error {
+	var all map[string]interface{}
+	if err := json.Unmarshal(b, &all); err != nil {
+		return err
+	}
+
+	*r = ServiceRule{}
+	r.ID, _ = all["_id"].(string)
+	r.CreatedAt, _ = all["createdAt"].(string)
+	r.UpdatedAt, _ = all["updateAt"].(string)
+	for _, k := range []string{"_id", "createdAt", "updateAt", "updatedAt"} {
+		delete(all, k)
+	}
+	if len(all) > 0 {
+		r.Fields = all
+	}
+	return nil
+}
+
+// MarshalJSON encodes Fields together with the rule ID. Timestamps are
+// read-only and are not sent.
+func (r ServiceRule) MarshalJSON() ([]byte, error) {
+	out := make(map[string]interface{}, len(r.Fields)+1)
+	for k, v := range r.Fields {
+		out[k] = v
+	}
+	if r.ID != "" {
+		out["_id"] = r.ID
+	}
+	return json.Marshal(out)
 }
 
 // ListServiceRulesResponse contains paginated service rule results.
@@ -67,6 +106,18 @@ func (s *ServiceRulesService) List(ctx context.Context, serviceID string, opts L
 	return &resp, nil
 }
 
+// All iterates over all rules of a service, fetching opts.Limit items per page.
+func (s *ServiceRulesService) All(ctx context.Context, serviceID string, opts ListServiceRulesOptions) iter.Seq2[ServiceRule, error] {
+	return paginate(ctx, opts.Offset, opts.Limit, func(ctx context.Context, offset, limit int) ([]ServiceRule, MetaInfo, error) {
+		opts.Offset, opts.Limit = offset, limit
+		resp, err := s.List(ctx, serviceID, opts)
+		if err != nil {
+			return nil, MetaInfo{}, err
+		}
+		return resp.Rules, resp.Meta, nil
+	})
+}
+
 // Update performs a bulk update of rules for a service.
 func (s *ServiceRulesService) Update(ctx context.Context, serviceID string, req UpdateServiceRulesRequest) (*ListServiceRulesResponse, error) {
 	if serviceID == "" {
@@ -2,6 +2,7 @@ package v2_6
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -127,3 +128,24 @@ func TestServiceRulesService_ErrorHandling(t *testing.T) {
 		t.Errorf("Expected 'serviceID is required' error, got %s", err.Error())
 	}
 }
+
+func TestServiceRule_JSON(t *testing.T) {
+	var rule ServiceRule
+	if err := json.Unmarshal([]byte(`{"_id":"rule-1","createdAt":"2024-01-01","updateAt":"2024-01-02","match":"/static/*","ttl":3600}`), &rule); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rule.ID != "rule-1" || rule.UpdatedAt != "2024-01-02" {
+		t.Errorf("Unexpected rule %+v", rule)
+	}
+	if rule.Fields["match"] != "/static/*" || len(rule.Fields) != 2 {
+		t.Errorf("Expected rule body in Fields, got %v", rule.Fields)
+	}
+
+	out, err := json.Marshal(rule)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(out) != `{"_id":"rule-1","match":"/static/*","ttl":3600}` {
+		t.Errorf("Unexpected JSON %s", out)
+	}
+}
@@ -12,80 +12,71 @@ type ServiceStatsService struct {
 	Client *httpclient.Client
 }
 
-func (s *ServiceStatsService) get(ctx context.Context, sid string, endpoint string, opts StatsQueryOptions) (*StatsResponse, error) {
+func getServiceStats[T any](ctx context.Context, client *httpclient.Client, sid string, endpoint string, opts StatsQueryOptions) (*StatsResult[T], error) {
 	if sid == "" {
 		return nil, fmt.Errorf("service id is required")
 	}
-	params := opts.toURLValues()
-	url := fmt.Sprintf("/services/%s/stats/%s", sid, endpoint)
-	if len(params) > 0 {
-		url = fmt.Sprintf("%s?%s", url, params.Encode())
-	}
-	var out StatsResponse
-	if err := s.Client.Get(ctx, url, &out); err != nil {
-		return nil, err
-	}
-	return &out, nil
+	return getStats[T](ctx, client, fmt.Sprintf("/services/%s/stats/%s", sid, endpoint), opts)
 }
 
 // POP returns service POP stats.
 // Docs: https://portal.cachefly.com/api/2.6/docs/#tag/Service-Stats
-func (s *ServiceStatsService) POP(ctx context.Context, sid string, opts StatsQueryOptions) (*StatsResponse, error) {
-	if opts.From == "" || opts.To == "" {
-		return nil, fmt.Errorf("'from' and 'to' parameters are required")
+func (s *ServiceStatsService) POP(ctx context.Context, sid string, opts StatsQueryOptions) (*StatsResult[POPStatsRow], error) {
+	if err := opts.validatePeriod(); err != nil {
+		return nil, err
 	}
-	return s.get(ctx, sid, "pop", opts)
+	return getServiceStats[POPStatsRow](ctx, s.Client, sid, "pop", opts)
 }
 
 // Country returns service country stats.
-func (s *ServiceStatsService) Country(ctx context.Context, sid string, opts StatsQueryOptions) (*StatsResponse, error) {
-	if opts.From == "" || opts.To == "" {
-		return nil, fmt.Errorf("'from' and 'to' parameters are required")
+func (s *ServiceStatsService) Country(ctx context.Context, sid string, opts StatsQueryOptions) (*StatsResult[CountryStatsRow], error) {
+	if err := opts.validatePeriod(); err != nil {
+		return nil, err
 	}
-	return s.get(ctx, sid, "country", opts)
+	return getServiceStats[CountryStatsRow](ctx, s.Client, sid, "country", opts)
 }
 
 // Cache returns service cache stats.
-func (s *ServiceStatsService) Cache(ctx context.Context, sid string, opts StatsQueryOptions) (*StatsResponse, error) {
-	if opts.From == "" || opts.To == "" {
-		return nil, fmt.Errorf("'from' and 'to' parameters are required")
+func (s *ServiceStatsService) Cache(ctx context.Context, sid string, opts StatsQueryOptions) (*StatsResult[CacheStatsRow], error) {
+	if err := opts.validatePeriod(); err != nil {
+		return nil, err
 	}
-	return s.get(ctx, sid, "cache", opts)
+	return getServiceStats[CacheStatsRow](ctx, s.Client, sid, "cache", opts)
 }
 
 // Status returns service status stats.
-func (s *ServiceStatsService) Status(ctx context.Context, sid string, opts StatsQueryOptions) (*StatsResponse, error) {
-	if opts.From == "" || opts.To == "" {
-		return nil, fmt.Errorf("'from' and 'to' parameters are required")
+func (s *ServiceStatsService) Status(ctx context.Context, sid string, opts StatsQueryOptions) (*StatsResult[StatusStatsRow], error) {
+	if err := opts.validatePeriod(); err != nil {
+		return nil, err
 	}
-	return s.get(ctx, sid, "status", opts)
+	return getServiceStats[StatusStatsRow](ctx, s.Client, sid, "status", opts)
 }
 
 // Realtime returns service realtime stats.
-func (s *ServiceStatsService) Realtime(ctx context.Context, sid string, opts StatsQueryOptions) (*StatsResponse, error) {
-	return s.get(ctx, sid, "realtime", opts)
+func (s *ServiceStatsService) Realtime(ctx context.Context, sid string, opts StatsQueryOptions) (*StatsResult[RealtimeStatsRow], error) {
+	return getServiceStats[RealtimeStatsRow](ctx, s.Client, sid, "realtime", opts)
 }
 
 // Path returns service path stats.
-func (s *ServiceStatsService) Path(ctx context.Context, sid string, opts StatsQueryOptions) (*StatsResponse, error) {
-	if opts.From == "" || opts.To == "" {
-		return nil, fmt.Errorf("'from' and 'to' parameters are required")
+func (s *ServiceStatsService) Path(ctx context.Context, sid string, opts StatsQueryOptions) (*StatsResult[PathStatsRow], error) {
+	if err := opts.validatePeriod(); err != nil {
+		return nil, err
 	}
-	return s.get(ctx, sid, "path", opts)
+	return getServiceStats[PathStatsRow](ctx, s.Client, sid, "path", opts)
 }
 
 // Referer returns service referer stats.
-func (s *ServiceStatsService) Referer(ctx context.Context, sid string, opts StatsQueryOptions) (*StatsResponse, error) {
-	if opts.From == "" || opts.To == "" {
-		return nil, fmt.Errorf("'from' and 'to' parameters are required")
+func (s *ServiceStatsService) Referer(ctx context.Context, sid string, opts StatsQueryOptions) (*StatsResult[RefererStatsRow], error) {
+	if err := opts.validatePeriod(); err != nil {
+		return nil, err
 	}
-	return s.get(ctx, sid, "referer", opts)
+	return getServiceStats[RefererStatsRow](ctx, s.Client, sid, "referer", opts)
 }
 
 // Origin returns service origin stats.
-func (s *ServiceStatsService) Origin(ctx context.Context, sid string, opts StatsQueryOptions) (*StatsResponse, error) {
-	if opts.From == "" || opts.To == "" {
-		return nil, fmt.Errorf("'from' and 'to' parameters are required")
+func (s *ServiceStatsService) Origin(ctx context.Context, sid string, opts StatsQueryOptions) (*StatsResult[OriginStatsRow], error) {
+	if err := opts.validatePeriod(); err != nil {
+		return nil, err
 	}
-	return s.get(ctx, sid, "origin", opts)
+	return getServiceStats[OriginStatsRow](ctx, s.Client, sid, "origin", opts)
 }
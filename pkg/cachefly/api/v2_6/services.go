@@ -3,6 +3,7 @@ package v2_6
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 
@@ -161,6 +162,18 @@ func (s *ServicesService) List(ctx context.Context, opts ListOptions) (*ListServ
 	return &result, nil
 }
 
+// All iterates over all services, fetching opts.Limit items per page.
+func (s *ServicesService) All(ctx context.Context, opts ListOptions) iter.Seq2[Service, error] {
+	return paginate(ctx, opts.Offset, opts.Limit, func(ctx context.Context, offset, limit int) ([]Service, MetaInfo, error) {
+		opts.Offset, opts.Limit = offset, limit
+		resp, err := s.List(ctx, opts)
+		if err != nil {
+			return nil, MetaInfo{}, err
+		}
+		return resp.Services, resp.Meta, nil
+	})
+}
+
 // UpdateServiceByID updates an existing service configuration.
 func (s *ServicesService) UpdateServiceByID(ctx context.Context, id string, req UpdateServiceRequest) (*Service, error) {
 	if id == "" {
@@ -1,8 +1,15 @@
 package v2_6
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
 	"net/url"
 	"strconv"
+	"time"
+
+	"github.com/cachefly/cachefly-sdk-go/internal/httpclient"
 )
 
 // StatsMeta contains metadata about a stats response.
@@ -14,23 +21,57 @@ type StatsMeta struct {
 	GroupBy []string `json:"groupBy,omitempty"`
 }
 
-// StatsDataPoint represents a single stats row. Fields differ by endpoint,
-// so we keep it flexible as a generic map.
+// StatsDataPoint represents a single untyped stats row as returned by the API.
 type StatsDataPoint map[string]interface{}
 
-// StatsResponse is a generic response shape returned by stats endpoints.
-type StatsResponse struct {
-	Meta StatsMeta        `json:"meta"`
-	Data []StatsDataPoint `json:"data"`
+// StatsResult is the response of a stats endpoint with rows decoded into T.
+type StatsResult[T any] struct {
+	Meta StatsMeta `json:"meta"`
+	Data []T       `json:"data"`
+}
+
+// StatsResponse is a stats response with untyped rows.
+type StatsResponse = StatsResult[StatsDataPoint]
+
+// UnmarshalJSON decodes the response and fills the shared StatsRow fields of
+// typed rows from the raw row values.
+func (r *StatsResult[T]) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		Meta StatsMeta         `json:"meta"`
+		Data []json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	r.Meta = raw.Meta
+	r.Data = make([]T, 0, len(raw.Data))
+	for _, item := range raw.Data {
+		var row T
+		if err := json.Unmarshal(item, &row); err != nil {
+			return fmt.Errorf("failed to decode stats row: %w", err)
+		}
+		if base, ok := any(&row).(interface{ setRaw(StatsDataPoint) }); ok {
+			var point StatsDataPoint
+			if err := json.Unmarshal(item, &point); err != nil {
+				return fmt.Errorf("failed to decode stats row: %w", err)
+			}
+			base.setRaw(point)
+		}
+		r.Data = append(r.Data, row)
+	}
+	return nil
 }
 
 // StatsQueryOptions defines common query parameters for stats endpoints.
 // Refer to API docs for the precise field availability per endpoint.
 type StatsQueryOptions struct {
-	Offset      int
-	Limit       int
-	From        string
-	To          string
+	Offset int
+	Limit  int
+	// From and To bound the reporting period. Times at midnight UTC are sent
+	// as dates, any other time as RFC 3339.
+	From        time.Time
+	To          time.Time
 	GroupBy     []string
 	SortBy      []string
 	IncludeInfo bool
@@ -42,6 +83,16 @@ type StatsQueryOptions struct {
 	TldOnly     bool
 }
 
+func (o StatsQueryOptions) validatePeriod() error {
+	if o.From.IsZero() || o.To.IsZero() {
+		return fmt.Errorf("'from' and 'to' parameters are required")
+	}
+	if o.To.Before(o.From) {
+		return fmt.Errorf("'to' must not be before 'from'")
+	}
+	return nil
+}
+
 func (o StatsQueryOptions) toURLValues() url.Values {
 	v := url.Values{}
 
@@ -51,11 +102,11 @@ func (o StatsQueryOptions) toURLValues() url.Values {
 	if o.Limit > 0 {
 		v.Set("limit", strconv.Itoa(o.Limit))
 	}
-	if o.From != "" {
-		v.Set("from", o.From)
+	if !o.From.IsZero() {
+		v.Set("from", formatStatsTime(o.From))
 	}
-	if o.To != "" {
-		v.Set("to", o.To)
+	if !o.To.IsZero() {
+		v.Set("to", formatStatsTime(o.To))
 	}
 
 	for _, g := range o.GroupBy {
@@ -99,3 +150,91 @@ func (o StatsQueryOptions) toURLValues() url.Values {
 
 	return v
 }
+
+func formatStatsTime(t time.Time) string {
+	t = t.UTC()
+	if t.Equal(t.Truncate(24 * time.Hour)) {
+		return t.Format(time.DateOnly)
+	}
+	return t.Format(time.RFC3339)
+}
+
+// getStats performs a GET against a stats endpoint and decodes rows into T.
+func getStats[T any](ctx context.Context, client *httpclient.Client, endpoint string, opts StatsQueryOptions) (*StatsResult[T], error) {
+	params := opts.toURLValues()
+	if len(params) > 0 {
+		endpoint = fmt.Sprintf("%s?%s", endpoint, params.Encode())
+	}
+
+	var out StatsResult[T]
+	if err := client.Get(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// statsTimeKeys are the row fields that may hold the time bucket of a row,
+// in order of preference.
+var statsTimeKeys = []string{"time", "date", "datetime", "timestamp", "hour", "minute"}
+
+// Timestamp returns the time bucket of the row, or the zero time if the row
+// was not grouped by time.
+func (p StatsDataPoint) Timestamp() time.Time {
+	for _, key := range statsTimeKeys {
+		if t, ok := parseStatsTime(p[key]); ok {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Dimension returns the value of a groupBy dimension as text.
+func (p StatsDataPoint) Dimension(name string) string {
+	switch v := p[name].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		if v == math.Trunc(v) {
+			return strconv.FormatFloat(v, 'f', 0, 64)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Value returns a numeric field of the row, or 0 if it is absent or not a number.
+func (p StatsDataPoint) Value(name string) float64 {
+	switch v := p[name].(type) {
+	case float64:
+		return v
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	}
+	return 0
+}
+
+func parseStatsTime(v interface{}) (time.Time, bool) {
+	switch t := v.(type) {
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, time.DateTime, "2006-01-02T15:04:05", "2006-01-02T15:04", time.DateOnly} {
+			if parsed, err := time.Parse(layout, t); err == nil {
+				return parsed, true
+			}
+		}
+	case float64:
+		// Unix timestamps in milliseconds are larger than any plausible value in seconds
+		if t > 1e11 {
+			return time.UnixMilli(int64(t)).UTC(), true
+		}
+		// Smaller numbers are more likely an hour or minute of the day than
+		// a time before 2001
+		if t > 1e9 {
+			return time.Unix(int64(t), 0).UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
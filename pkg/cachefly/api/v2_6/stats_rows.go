@@ -0,0 +1,178 @@
+package v2_6
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// StatsRow holds the metrics shared by every stats row. It is embedded in the
+// endpoint specific row types; the untyped row stays available through Raw
+// for fields the typed structs do not cover.
+type StatsRow struct {
+	Requests float64 `json:"requests"`
+	Bytes    float64 `json:"bytes"`
+
+	raw StatsDataPoint
+}
+
+func (r *StatsRow) setRaw(p StatsDataPoint) { r.raw = p }
+
+// Raw returns the row as returned by the API.
+func (r StatsRow) Raw() StatsDataPoint { return r.raw }
+
+// Timestamp returns the time bucket of the row, or the zero time if the
+// request was not grouped by time.
+func (r StatsRow) Timestamp() time.Time { return r.raw.Timestamp() }
+
+// Dimension returns the value of a groupBy dimension as text.
+func (r StatsRow) Dimension(name string) string { return r.raw.Dimension(name) }
+
+// Value returns a numeric field of the row by name.
+func (r StatsRow) Value(name string) float64 { return r.raw.Value(name) }
+
+// POPStatsRow is a row of the POP stats endpoints.
+type POPStatsRow struct {
+	StatsRow
+	POP string `json:"pop"`
+}
+
+// CountryStatsRow is a row of the country stats endpoints.
+type CountryStatsRow struct {
+	StatsRow
+	Country string `json:"country"`
+}
+
+// CacheStatsRow is a row of the cache stats endpoints.
+type CacheStatsRow struct {
+	StatsRow
+	Hits   float64 `json:"hits"`
+	Misses float64 `json:"misses"`
+}
+
+// HitRatio returns hits / (hits + misses), or 0 when there was no traffic.
+func (r CacheStatsRow) HitRatio() float64 {
+	if r.Hits+r.Misses == 0 {
+		return 0
+	}
+	return r.Hits / (r.Hits + r.Misses)
+}
+
+// StatusStatsRow is a row of the status code stats endpoints.
+type StatusStatsRow struct {
+	StatsRow
+	Status int `json:"-"`
+}
+
+// the API may send the status code as a number or a string
+func (r *StatusStatsRow) setRaw(p StatsDataPoint) {
+	r.StatsRow.setRaw(p)
+	r.Status = int(p.Value("status"))
+}
+
+// OriginStatsRow is a row of the origin stats endpoints.
+type OriginStatsRow struct {
+	StatsRow
+}
+
+// StorageStatsRow is a row of the storage stats endpoint.
+type StorageStatsRow struct {
+	StatsRow
+	Storage float64 `json:"storage"`
+}
+
+// PathStatsRow is a row of the path stats endpoints.
+type PathStatsRow struct {
+	StatsRow
+	Path string `json:"path"`
+}
+
+// RefererStatsRow is a row of the referer stats endpoints.
+type RefererStatsRow struct {
+	StatsRow
+	Referer string `json:"referer"`
+}
+
+// RealtimeStatsRow is a row of the realtime stats endpoints.
+type RealtimeStatsRow struct {
+	StatsRow
+}
+
+// StatsRecord is implemented by every stats row type and by StatsDataPoint.
+type StatsRecord interface {
+	Timestamp() time.Time
+	Dimension(name string) string
+}
+
+// TimePoint is a single value of a time series.
+type TimePoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// TimeSeries is the sequence of values for one combination of dimension values.
+type TimeSeries struct {
+	Labels map[string]string
+	Points []TimePoint
+}
+
+// Key returns the labels formatted as "dim=value,..." in the order they were
+// passed to Pivot.
+func (s TimeSeries) Key(dims ...string) string {
+	parts := make([]string, 0, len(dims))
+	for _, d := range dims {
+		parts = append(parts, d+"="+s.Labels[d])
+	}
+	return strings.Join(parts, ",")
+}
+
+// Pivot groups rows into one time series per distinct combination of the
+// given dimensions, extracting each point with value. Rows that share a
+// series and a timestamp are summed. Series are sorted by their labels and
+// points by time.
+//
+// Example:
+//
+//	res, _ := client.AccountStats.POP(ctx, v2_6.StatsQueryOptions{From: from, To: to, GroupBy: []string{"pop", "date"}})
+//	series := v2_6.Pivot(res.Data, func(r v2_6.POPStatsRow) float64 { return r.Bytes }, "pop")
+func Pivot[T StatsRecord](rows []T, value func(T) float64, dims ...string) []TimeSeries {
+	type bucket struct {
+		series TimeSeries
+		points map[time.Time]float64
+	}
+
+	buckets := map[string]*bucket{}
+	for _, row := range rows {
+		labels := make(map[string]string, len(dims))
+		for _, d := range dims {
+			labels[d] = row.Dimension(d)
+		}
+		key := TimeSeries{Labels: labels}.Key(dims...)
+
+		b, ok := buckets[key]
+		if !ok {
+			b = &bucket{series: TimeSeries{Labels: labels}, points: map[time.Time]float64{}}
+			buckets[key] = b
+		}
+		b.points[row.Timestamp()] += value(row)
+	}
+
+	keys := make([]string, 0, len(buckets))
+	for k := range buckets {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]TimeSeries, 0, len(keys))
+	for _, k := range keys {
+		b := buckets[k]
+		for t, v := range b.points {
+			b.series.Points = append(b.series.Points, TimePoint{Time: t, Value: v})
+		}
+		sort.Slice(b.series.Points, func(i, j int) bool {
+			return b.series.Points[i].Time.Before(b.series.Points[j].Time)
+		})
+		out = append(out, b.series)
+	}
+	return out
+}
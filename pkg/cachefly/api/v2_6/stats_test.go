@@ -0,0 +1,125 @@
+package v2_6
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cachefly/cachefly-sdk-go/internal/httpclient"
+)
+
+func TestAccountStatsService_POP_Typed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/2.6/stats/pop" {
+			t.Errorf("Expected path /api/2.6/stats/pop, got %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("from") != "2024-09-01" || q.Get("to") != "2024-09-02T12:00:00Z" {
+			t.Errorf("Unexpected period from=%s to=%s", q.Get("from"), q.Get("to"))
+		}
+		w.Write([]byte(`{"meta":{"groupBy":["pop","date"]},"data":[
+			{"pop":"ams","date":"2024-09-02","requests":5,"bytes":500},
+			{"pop":"ams","date":"2024-09-01","requests":10,"bytes":1000,"extra":7},
+			{"pop":"lax","date":"2024-09-01","requests":1,"bytes":100}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "test-token"})
+	svc := &AccountStatsService{Client: client}
+
+	resp, err := svc.POP(context.Background(), StatsQueryOptions{
+		From:    time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+		To:      time.Date(2024, 9, 2, 12, 0, 0, 0, time.UTC),
+		GroupBy: []string{"pop", "date"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(resp.Data) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(resp.Data))
+	}
+	row := resp.Data[1]
+	if row.POP != "ams" || row.Requests != 10 || row.Bytes != 1000 {
+		t.Errorf("Unexpected row %+v", row)
+	}
+	if !row.Timestamp().Equal(time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected timestamp %v", row.Timestamp())
+	}
+	if row.Value("extra") != 7 {
+		t.Errorf("Expected raw field to be kept, got %v", row.Raw())
+	}
+
+	series := Pivot(resp.Data, func(r POPStatsRow) float64 { return r.Bytes }, "pop")
+	if len(series) != 2 {
+		t.Fatalf("Expected 2 series, got %d", len(series))
+	}
+	ams := series[0]
+	if ams.Key("pop") != "pop=ams" || len(ams.Points) != 2 {
+		t.Fatalf("Unexpected series %+v", ams)
+	}
+	if ams.Points[0].Value != 1000 || ams.Points[1].Value != 500 {
+		t.Errorf("Expected points sorted by time, got %+v", ams.Points)
+	}
+}
+
+func TestServiceStatsService_Status_Typed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/2.6/services/svc-1/stats/status" {
+			t.Errorf("Expected path /api/2.6/services/svc-1/stats/status, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"meta":{},"data":[{"status":"404","requests":3},{"status":200,"requests":9}]}`))
+	}))
+	defer server.Close()
+
+	client := httpclient.New(httpclient.Config{BaseURL: server.URL + "/api/2.6", AuthToken: "test-token"})
+	svc := &ServiceStatsService{Client: client}
+
+	day := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	resp, err := svc.Status(context.Background(), "svc-1", StatsQueryOptions{From: day, To: day.AddDate(0, 0, 1)})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Data[0].Status != 404 || resp.Data[1].Status != 200 {
+		t.Errorf("Unexpected status codes %+v", resp.Data)
+	}
+}
+
+func TestStatsQueryOptions_ValidatePeriod(t *testing.T) {
+	svc := &AccountStatsService{}
+	day := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := svc.Cache(context.Background(), StatsQueryOptions{From: day}); err == nil {
+		t.Error("Expected error for missing 'to'")
+	}
+	if _, err := svc.Cache(context.Background(), StatsQueryOptions{From: day, To: day.Add(-time.Hour)}); err == nil {
+		t.Error("Expected error for 'to' before 'from'")
+	}
+}
+
+func TestCacheStatsRow_HitRatio(t *testing.T) {
+	if r := (CacheStatsRow{Hits: 3, Misses: 1}).HitRatio(); r != 0.75 {
+		t.Errorf("Expected 0.75, got %v", r)
+	}
+	if r := (CacheStatsRow{}).HitRatio(); r != 0 {
+		t.Errorf("Expected 0 without traffic, got %v", r)
+	}
+}
+
+func TestStatsDataPoint_Timestamp(t *testing.T) {
+	tests := []struct {
+		point StatsDataPoint
+		want  time.Time
+	}{
+		{StatsDataPoint{"timestamp": float64(1725148800)}, time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{StatsDataPoint{"timestamp": float64(1725148800000)}, time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{StatsDataPoint{"hour": float64(13)}, time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := tt.point.Timestamp(); !got.Equal(tt.want) {
+			t.Errorf("Expected %v for %v, got %v", tt.want, tt.point, got)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package v2_6
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 
@@ -66,6 +67,18 @@ func (s *TLSProfilesService) List(ctx context.Context, opts ListTLSProfilesOptio
 	return &resp, nil
 }
 
+// All iterates over all TLS profiles, fetching opts.Limit items per page.
+func (s *TLSProfilesService) All(ctx context.Context, opts ListTLSProfilesOptions) iter.Seq2[TLSProfile, error] {
+	return paginate(ctx, opts.Offset, opts.Limit, func(ctx context.Context, offset, limit int) ([]TLSProfile, MetaInfo, error) {
+		opts.Offset, opts.Limit = offset, limit
+		resp, err := s.List(ctx, opts)
+		if err != nil {
+			return nil, MetaInfo{}, err
+		}
+		return resp.Profiles, resp.Meta, nil
+	})
+}
+
 // GetByID retrieves a TLS profile by its ID.
 func (s *TLSProfilesService) GetByID(ctx context.Context, id string) (*TLSProfile, error) {
 	if id == "" {
@@ -3,6 +3,7 @@ package v2_6
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 
@@ -114,6 +115,18 @@ func (u *UsersService) List(ctx context.Context, opts ListUsersOptions) (*ListUs
 	return &resp, nil
 }
 
+// All iterates over all users, fetching opts.Limit items per page.
+func (u *UsersService) All(ctx context.Context, opts ListUsersOptions) iter.Seq2[User, error] {
+	return paginate(ctx, opts.Offset, opts.Limit, func(ctx context.Context, offset, limit int) ([]User, MetaInfo, error) {
+		opts.Offset, opts.Limit = offset, limit
+		resp, err := u.List(ctx, opts)
+		if err != nil {
+			return nil, MetaInfo{}, err
+		}
+		return resp.Users, resp.Meta, nil
+	})
+}
+
 // Create adds a new user account.
 func (u *UsersService) Create(ctx context.Context, req CreateUserRequest) (*User, error) {
 	var created User
@@ -0,0 +1,482 @@
+// Package apply reconciles a CacheFly service with a declarative spec.
+//
+// An Engine reads the current state of a service, compares it with a
+// ServiceSpec and produces a Plan: the list of changes to domains, options,
+// referer rules, rules, image optimization and logging needed to reach the
+// desired state. Plans can be reviewed (Plan.String renders a diff) before
+// they are applied.
+//
+// Example:
+//
+//	spec, err := apply.LoadSpec(f)
+//	engine := apply.New(client)
+//	plan, err := engine.Plan(ctx, *spec)
+//	fmt.Print(plan)
+//	err = engine.Apply(ctx, plan)
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+)
+
+// Engine plans and applies service specs.
+type Engine struct {
+	client *cachefly.Client
+}
+
+// New returns an Engine using client for all API calls.
+func New(client *cachefly.Client) *Engine {
+	return &Engine{client: client}
+}
+
+// Plan reads the current state of the service and returns the changes
+// needed to match spec. Changes are ordered so that dependencies come first:
+// domains, options, referer rules, rules, image optimization, then logging.
+// Within each section creates and updates come before deletes.
+func (e *Engine) Plan(ctx context.Context, spec ServiceSpec) (*Plan, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	sid, err := e.resolveService(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{ServiceID: sid}
+	steps := []func(context.Context, string, ServiceSpec) ([]Change, error){
+		e.planDomains,
+		e.planOptions,
+		e.planRefererRules,
+		e.planRules,
+		e.planImageOptimization,
+		e.planLogging,
+	}
+	for _, step := range steps {
+		changes, err := step(ctx, sid, spec)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(changes, func(i, j int) bool {
+			return changes[i].Action != ActionDelete && changes[j].Action == ActionDelete
+		})
+		plan.Changes = append(plan.Changes, changes...)
+	}
+	return plan, nil
+}
+
+// Apply executes the changes of plan in order. It stops at the first
+// failure; changes applied before it are not rolled back, so running Plan
+// again shows what is left to do.
+func (e *Engine) Apply(ctx context.Context, plan *Plan) error {
+	for _, c := range plan.Changes {
+		if c.apply == nil {
+			return fmt.Errorf("change %s was not created by Engine.Plan", c)
+		}
+		if err := c.apply(ctx); err != nil {
+			return fmt.Errorf("failed to %s %s %q: %w", c.Action, c.Resource, c.Name, err)
+		}
+	}
+	return nil
+}
+
+func (e *Engine) resolveService(ctx context.Context, spec ServiceSpec) (string, error) {
+	if spec.ServiceID != "" {
+		return spec.ServiceID, nil
+	}
+	for svc, err := range e.client.Services.All(ctx, api.ListOptions{}) {
+		if err != nil {
+			return "", fmt.Errorf("failed to list services: %w", err)
+		}
+		if svc.UniqueName == spec.UniqueName {
+			return svc.ID, nil
+		}
+	}
+	return "", fmt.Errorf("service %q not found", spec.UniqueName)
+}
+
+func (e *Engine) planDomains(ctx context.Context, sid string, spec ServiceSpec) ([]Change, error) {
+	if spec.Domains == nil {
+		return nil, nil
+	}
+
+	current := map[string]api.ServiceDomain{}
+	for d, err := range e.client.ServiceDomains.All(ctx, sid, api.ListServiceDomainsOptions{}) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list domains: %w", err)
+		}
+		current[d.Name] = d
+	}
+
+	var changes []Change
+	for _, want := range spec.Domains {
+		have, ok := current[want.Name]
+		delete(current, want.Name)
+		if !ok {
+			req := api.CreateServiceDomainRequest{Name: want.Name, Description: want.Description, ValidationMode: want.ValidationMode}
+			changes = append(changes, Change{
+				Resource: "domain", Name: want.Name, Action: ActionCreate, After: want,
+				apply: func(ctx context.Context) error {
+					_, err := e.client.ServiceDomains.Create(ctx, sid, req)
+					return err
+				},
+			})
+			continue
+		}
+
+		before := DomainSpec{Name: have.Name, Description: have.Description, ValidationMode: have.ValidationMode}
+		after := want
+		if after.ValidationMode == "" {
+			// not set in the spec: keep whatever mode the domain uses
+			after.ValidationMode = before.ValidationMode
+		}
+		if before == after {
+			continue
+		}
+		req := api.UpdateServiceDomainRequest{Name: want.Name, Description: want.Description, ValidationMode: want.ValidationMode}
+		changes = append(changes, Change{
+			Resource: "domain", Name: want.Name, Action: ActionUpdate, Before: before, After: after,
+			apply: func(ctx context.Context) error {
+				_, err := e.client.ServiceDomains.UpdateByID(ctx, sid, have.ID, req)
+				return err
+			},
+		})
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(current)) {
+		have := current[name]
+		changes = append(changes, Change{
+			Resource: "domain", Name: name, Action: ActionDelete,
+			Before: DomainSpec{Name: have.Name, Description: have.Description, ValidationMode: have.ValidationMode},
+			apply: func(ctx context.Context) error {
+				return e.client.ServiceDomains.DeleteByID(ctx, sid, have.ID)
+			},
+		})
+	}
+	return changes, nil
+}
+
+func (e *Engine) planOptions(ctx context.Context, sid string, spec ServiceSpec) ([]Change, error) {
+	if len(spec.Options) == 0 {
+		return nil, nil
+	}
+
+	options, err := e.client.ServiceOptions.PlanOptions(ctx, sid, spec.Options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan options: %w", err)
+	}
+
+	before := api.ServiceOptions{}
+	after := api.ServiceOptions{}
+	for _, c := range options.Changes {
+		name, _, _ := strings.Cut(c.Path, ".")
+		switch name {
+		case "protectServeKey":
+			// The spec asks for a ProtectServe key and the service has one;
+			// regenerating it on every apply would break signed URLs.
+			continue
+		case api.OptionProtectServe:
+			before[name] = c.Old
+		default:
+			before[name] = options.Current[name]
+		}
+		after[name] = spec.Options[name]
+	}
+	if len(after) == 0 {
+		return nil, nil
+	}
+
+	return []Change{{
+		Resource: "options", Name: strings.Join(slices.Sorted(maps.Keys(after)), ", "), Action: ActionUpdate,
+		Before: before, After: after,
+		apply: func(ctx context.Context) error {
+			update := make(api.ServiceOptions, len(after))
+			for k, v := range after {
+				update[k] = v
+			}
+			_, err := e.client.ServiceOptions.UpdateOptions(ctx, sid, update)
+			return err
+		},
+	}}, nil
+}
+
+func (e *Engine) planRefererRules(ctx context.Context, sid string, spec ServiceSpec) ([]Change, error) {
+	if spec.RefererRules == nil {
+		return nil, nil
+	}
+
+	current := map[string]api.RefererRule{}
+	for r, err := range e.client.ServiceOptionsRefererRules.All(ctx, sid, api.ListRefererRulesOptions{}) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list referer rules: %w", err)
+		}
+		current[refererRuleKey(r.Directory, r.Extension)] = r
+	}
+
+	var changes []Change
+	for _, want := range spec.RefererRules {
+		key := refererRuleKey(want.Directory, want.Extension)
+		have, ok := current[key]
+		delete(current, key)
+		if !ok {
+			req := api.CreateRefererRuleRequest{Directory: want.Directory, Extension: want.Extension, Exceptions: want.Exceptions, DefaultAction: want.DefaultAction}
+			if req.Exceptions == nil {
+				req.Exceptions = []string{}
+			}
+			changes = append(changes, Change{
+				Resource: "referer rule", Name: key, Action: ActionCreate, After: want,
+				apply: func(ctx context.Context) error {
+					_, err := e.client.ServiceOptionsRefererRules.Create(ctx, sid, req)
+					return err
+				},
+			})
+			continue
+		}
+
+		before := RefererRuleSpec{Directory: have.Directory, Extension: have.Extension, Exceptions: have.Exceptions, DefaultAction: have.DefaultAction}
+		if before.DefaultAction == want.DefaultAction && slices.Equal(before.Exceptions, want.Exceptions) {
+			continue
+		}
+		req := api.UpdateRefererRuleRequest{Exceptions: want.Exceptions, DefaultAction: want.DefaultAction}
+		changes = append(changes, Change{
+			Resource: "referer rule", Name: key, Action: ActionUpdate, Before: before, After: want,
+			apply: func(ctx context.Context) error {
+				_, err := e.client.ServiceOptionsRefererRules.Update(ctx, sid, have.ID, req)
+				return err
+			},
+		})
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(current)) {
+		have := current[key]
+		changes = append(changes, Change{
+			Resource: "referer rule", Name: key, Action: ActionDelete,
+			Before: RefererRuleSpec{Directory: have.Directory, Extension: have.Extension, Exceptions: have.Exceptions, DefaultAction: have.DefaultAction},
+			apply: func(ctx context.Context) error {
+				return e.client.ServiceOptionsRefererRules.Delete(ctx, sid, have.ID)
+			},
+		})
+	}
+	return changes, nil
+}
+
+func (e *Engine) planRules(ctx context.Context, sid string, spec ServiceSpec) ([]Change, error) {
+	if spec.Rules == nil {
+		return nil, nil
+	}
+
+	// an empty rule set is [] on both sides, not null
+	before := []map[string]interface{}{}
+	for r, err := range e.client.ServiceRules.All(ctx, sid, api.ListServiceRulesOptions{}) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list rules: %w", err)
+		}
+		before = append(before, r.Fields)
+	}
+
+	after := make([]map[string]interface{}, 0, len(spec.Rules))
+	for _, r := range spec.Rules {
+		rule := map[string]interface{}{}
+		for k, v := range r {
+			if k != "_id" && k != "createdAt" && k != "updatedAt" && k != "updateAt" {
+				rule[k] = v
+			}
+		}
+		after = append(after, rule)
+	}
+	if len(before) == len(after) && equalJSON(before, after) {
+		return nil, nil
+	}
+
+	return []Change{{
+		Resource: "rules", Name: fmt.Sprintf("%d rules", len(after)), Action: ActionUpdate,
+		Before: before, After: after,
+		apply: func(ctx context.Context) error {
+			req := api.UpdateServiceRulesRequest{Rules: make([]api.ServiceRule, 0, len(after))}
+			for _, r := range after {
+				req.Rules = append(req.Rules, api.ServiceRule{Fields: r})
+			}
+			_, err := e.client.ServiceRules.Update(ctx, sid, req)
+			return err
+		},
+	}}, nil
+}
+
+func (e *Engine) planImageOptimization(ctx context.Context, sid string, spec ServiceSpec) ([]Change, error) {
+	if spec.ImageOptimization == nil {
+		return nil, nil
+	}
+
+	current, err := e.client.ServiceImageOptimization.GetConfiguration(ctx, sid)
+	if err != nil && !cachefly.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get image optimization configuration: %w", err)
+	}
+	want := spec.ImageOptimization.Config
+
+	switch {
+	case sameDocument(current, want):
+		return nil, nil
+	case strings.TrimSpace(want) == "":
+		return []Change{{
+			Resource: "image optimization", Name: sid, Action: ActionDelete, Before: current,
+			apply: func(ctx context.Context) error {
+				return e.client.ServiceImageOptimization.DeleteConfiguration(ctx, sid)
+			},
+		}}, nil
+	case strings.TrimSpace(current) == "":
+		return []Change{{
+			Resource: "image optimization", Name: sid, Action: ActionCreate, After: want,
+			apply: func(ctx context.Context) error {
+				// the create endpoint only takes the basic settings; the
+				// document itself is written by the update that follows
+				if _, err := e.client.ServiceImageOptimization.CreateConfiguration(ctx, sid, api.CreateImageOptimizationOptions{Enabled: true}); err != nil {
+					return err
+				}
+				_, err := e.client.ServiceImageOptimization.UpdateConfiguration(ctx, sid, want)
+				return err
+			},
+		}}, nil
+	default:
+		return []Change{{
+			Resource: "image optimization", Name: sid, Action: ActionUpdate, Before: current, After: want,
+			apply: func(ctx context.Context) error {
+				_, err := e.client.ServiceImageOptimization.UpdateConfiguration(ctx, sid, want)
+				return err
+			},
+		}}, nil
+	}
+}
+
+func (e *Engine) planLogging(ctx context.Context, sid string, spec ServiceSpec) ([]Change, error) {
+	if spec.Logging == nil {
+		return nil, nil
+	}
+
+	var targets []api.LogTarget
+	for t, err := range e.client.LogTargets.All(ctx, api.ListLogTargetsOptions{}) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list log targets: %w", err)
+		}
+		targets = append(targets, t)
+	}
+
+	var changes []Change
+	for _, kind := range []struct {
+		resource string
+		want     string
+		services func(api.LogTarget) *[]string
+		enable   func(ctx context.Context, target string) error
+		disable  func(ctx context.Context) error
+	}{
+		{
+			resource: "access logging",
+			want:     spec.Logging.AccessLogTarget,
+			services: func(t api.LogTarget) *[]string { return t.AccessLogsServices },
+			enable: func(ctx context.Context, target string) error {
+				_, err := e.client.Services.EnableAccessLogging(ctx, sid, api.EnableAccessLogsRequest{LogTarget: target})
+				return err
+			},
+			disable: func(ctx context.Context) error {
+				_, err := e.client.Services.DeleteAccessLoggingByID(ctx, sid)
+				return err
+			},
+		},
+		{
+			resource: "origin logging",
+			want:     spec.Logging.OriginLogTarget,
+			services: func(t api.LogTarget) *[]string { return t.OriginLogsServices },
+			enable: func(ctx context.Context, target string) error {
+				_, err := e.client.Services.EnableOriginLogging(ctx, sid, api.EnableOriginLogsRequest{LogTarget: target})
+				return err
+			},
+			disable: func(ctx context.Context) error {
+				_, err := e.client.Services.DeleteOriginLoggingByID(ctx, sid)
+				return err
+			},
+		},
+	} {
+		var have *api.LogTarget
+		for i := range targets {
+			if list := kind.services(targets[i]); list != nil && slices.Contains(*list, sid) {
+				have = &targets[i]
+				break
+			}
+		}
+
+		var want *api.LogTarget
+		if kind.want != "" {
+			for i := range targets {
+				if targets[i].ID == kind.want || (targets[i].Name != nil && *targets[i].Name == kind.want) {
+					want = &targets[i]
+					break
+				}
+			}
+			if want == nil {
+				return nil, fmt.Errorf("log target %q not found", kind.want)
+			}
+		}
+
+		switch {
+		case have == nil && want == nil, have != nil && want != nil && have.ID == want.ID:
+			continue
+		case want == nil:
+			changes = append(changes, Change{Resource: kind.resource, Name: sid, Action: ActionDelete, Before: logTargetName(have), apply: kind.disable})
+		default:
+			action, before := ActionCreate, interface{}(nil)
+			if have != nil {
+				action, before = ActionUpdate, logTargetName(have)
+			}
+			targetID, enable := want.ID, kind.enable
+			changes = append(changes, Change{
+				Resource: kind.resource, Name: sid, Action: action, Before: before, After: logTargetName(want),
+				apply: func(ctx context.Context) error { return enable(ctx, targetID) },
+			})
+		}
+	}
+	return changes, nil
+}
+
+func logTargetName(t *api.LogTarget) string {
+	if t.Name != nil && *t.Name != "" {
+		return *t.Name
+	}
+	return t.ID
+}
+
+// equalJSON compares two values by their JSON representation, so numbers
+// decoded from the API compare equal to ints written in a spec.
+func equalJSON(a, b interface{}) bool {
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+func normalize(v interface{}) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+// sameDocument compares two configuration documents, semantically when both
+// are JSON and by text otherwise.
+func sameDocument(a, b string) bool {
+	var da, db interface{}
+	if json.Unmarshal([]byte(a), &da) == nil && json.Unmarshal([]byte(b), &db) == nil {
+		return reflect.DeepEqual(da, db)
+	}
+	return strings.TrimSpace(a) == strings.TrimSpace(b)
+}
@@ -0,0 +1,240 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+)
+
+// fakeAPI serves canned GET responses and records every write request.
+type fakeAPI struct {
+	mu     sync.Mutex
+	get    map[string]string
+	writes []string
+	bodies map[string]string
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/2.6")
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == http.MethodGet {
+		body, ok := f.get[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"not found"}`))
+			return
+		}
+		w.Write([]byte(body))
+		return
+	}
+
+	key := r.Method + " " + path
+	b, _ := io.ReadAll(r.Body)
+	f.writes = append(f.writes, key)
+	f.bodies[key] = string(b)
+	if strings.Contains(path, "imageopt4") {
+		w.Write([]byte(`""`))
+		return
+	}
+	w.Write([]byte(`{}`))
+}
+
+func newFakeAPI(t *testing.T) (*fakeAPI, *cachefly.Client) {
+	f := &fakeAPI{
+		bodies: map[string]string{},
+		get: map[string]string{
+			"/services/svc-1/domains": `{"meta":{"count":2},"data":[
+				{"_id":"d1","name":"keep.example.com","validationMode":"HTTP"},
+				{"_id":"d2","name":"old.example.com"}]}`,
+			"/services/svc-1/options":          `{"cors":false,"ttl":3600}`,
+			"/services/svc-1/options/metadata": `{"data":[{"name":"CORS Override","type":"standard"},{"name":"ttl","type":"standard"}]}`,
+			"/services/svc-1/options/refererrules": `{"meta":{"count":1},"data":[
+				{"_id":"r1","directory":"/img","extension":"*.jpg","exceptions":["a.com"],"defaultAction":"deny"}]}`,
+			"/services/svc-1/rules": `{"meta":{"count":1},"data":[{"_id":"x","createdAt":"t","match":"/a","ttl":60}]}`,
+			"/logtargets": `{"meta":{"count":2},"data":[
+				{"_id":"lt1","name":"s3-main","accessLogsServices":["svc-1"]},
+				{"_id":"lt2","name":"elastic"}]}`,
+		},
+	}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	return f, cachefly.NewClient(cachefly.WithToken("test-token"), cachefly.WithBaseURL(server.URL+"/api/2.6"))
+}
+
+func TestEngine_PlanAndApply(t *testing.T) {
+	f, client := newFakeAPI(t)
+	engine := New(client)
+
+	spec := ServiceSpec{
+		ServiceID: "svc-1",
+		Domains: []DomainSpec{
+			{Name: "keep.example.com"},
+			{Name: "new.example.com"},
+		},
+		Options: map[string]interface{}{"cors": true, "ttl": 3600},
+		RefererRules: []RefererRuleSpec{
+			{Directory: "/img", Extension: "*.jpg", Exceptions: []string{"a.com", "b.com"}, DefaultAction: "deny"},
+		},
+		Rules:             []map[string]interface{}{{"match": "/a", "ttl": 60}},
+		ImageOptimization: &ImageOptimizationSpec{Config: `{"formats":["webp"]}`},
+		Logging:           &LoggingSpec{AccessLogTarget: "elastic"},
+	}
+
+	plan, err := engine.Plan(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var got []string
+	for _, c := range plan.Changes {
+		got = append(got, c.String())
+	}
+	want := []string{
+		`+ domain "new.example.com"`,
+		`- domain "old.example.com"`,
+		`~ options "cors"`,
+		`~ referer rule "/img *.jpg"`,
+		`+ image optimization "svc-1"`,
+		`~ access logging "svc-1"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Unexpected plan:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if out := plan.String(); !strings.Contains(out, "2 to create, 3 to update, 1 to delete") || !strings.Contains(out, `{"cors":false} => {"cors":true}`) {
+		t.Errorf("Unexpected plan output:\n%s", out)
+	}
+
+	if err := engine.Apply(context.Background(), plan); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	wantWrites := []string{
+		"POST /services/svc-1/domains",
+		"DELETE /services/svc-1/domains/d2",
+		"PUT /services/svc-1/options",
+		"PUT /services/svc-1/options/refererrules/r1",
+		"POST /services/svc-1/imageopt4",
+		"PUT /services/svc-1/imageopt4",
+		"PUT /services/svc-1/accessLogs",
+	}
+	if strings.Join(f.writes, "\n") != strings.Join(wantWrites, "\n") {
+		t.Errorf("Unexpected requests:\n%s", strings.Join(f.writes, "\n"))
+	}
+
+	var options map[string]interface{}
+	json.Unmarshal([]byte(f.bodies["PUT /services/svc-1/options"]), &options)
+	if len(options) != 1 || options["cors"] != true {
+		t.Errorf("Expected only the changed option to be sent, got %v", options)
+	}
+	if body := f.bodies["PUT /services/svc-1/accessLogs"]; !strings.Contains(body, `"lt2"`) {
+		t.Errorf("Expected log target resolved by name, got %s", body)
+	}
+}
+
+func TestEngine_Plan_UpToDate(t *testing.T) {
+	_, client := newFakeAPI(t)
+
+	plan, err := New(client).Plan(context.Background(), ServiceSpec{
+		ServiceID: "svc-1",
+		Options:   map[string]interface{}{"ttl": 3600},
+		Rules:     []map[string]interface{}{{"match": "/a", "ttl": 60}},
+		Logging:   &LoggingSpec{AccessLogTarget: "lt1"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !plan.Empty() {
+		t.Errorf("Expected empty plan, got:\n%s", plan)
+	}
+}
+
+func TestEngine_Plan_ProtectServe(t *testing.T) {
+	f, client := newFakeAPI(t)
+	engine := New(client)
+	spec := ServiceSpec{ServiceID: "svc-1", Options: map[string]interface{}{"protectServeKeyEnabled": true}}
+
+	plan, err := engine.Plan(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(plan.Changes) != 1 || plan.Changes[0].String() != `~ options "protectServeKeyEnabled"` {
+		t.Fatalf("Expected protectServeKeyEnabled to change, got:\n%s", plan)
+	}
+
+	f.get["/services/svc-1/options/protectserve"] = `{"protectServeKey":"hidden"}`
+	plan, err = engine.Plan(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !plan.Empty() {
+		t.Errorf("Expected an existing key to be left alone, got:\n%s", plan)
+	}
+}
+
+func TestEngine_Plan_UpToDateEmptyRules(t *testing.T) {
+	f, client := newFakeAPI(t)
+	f.get["/services/svc-1/rules"] = `{"meta":{"count":0},"data":[]}`
+
+	spec, err := LoadSpec(strings.NewReader(`{"serviceId":"svc-1","rules":[]}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	plan, err := New(client).Plan(context.Background(), *spec)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !plan.Empty() {
+		t.Errorf("Expected empty plan, got:\n%s", plan)
+	}
+}
+
+func TestEngine_Plan_ReplacesRules(t *testing.T) {
+	f, client := newFakeAPI(t)
+	engine := New(client)
+
+	plan, err := engine.Plan(context.Background(), ServiceSpec{
+		ServiceID: "svc-1",
+		Rules:     []map[string]interface{}{{"match": "/b", "ttl": 60}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(plan.Changes) != 1 || plan.Changes[0].Resource != "rules" {
+		t.Fatalf("Expected a single rules change, got %v", plan.Changes)
+	}
+	if err := engine.Apply(context.Background(), plan); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if body := f.bodies["PUT /services/svc-1/rules"]; body != `{"rules":[{"match":"/b","ttl":60}]}` {
+		t.Errorf("Unexpected rules body %s", body)
+	}
+}
+
+func TestLoadSpec(t *testing.T) {
+	spec, err := LoadSpec(strings.NewReader(`{"uniqueName":"web","domains":[]}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if spec.Domains == nil {
+		t.Error("Expected an empty domain list to be managed")
+	}
+	if spec.RefererRules != nil {
+		t.Error("Expected omitted referer rules to be unmanaged")
+	}
+
+	if _, err := LoadSpec(strings.NewReader(`{"serviceId":"svc-1","domain":[]}`)); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+	if _, err := LoadSpec(strings.NewReader(`{"domains":[]}`)); err == nil {
+		t.Error("Expected error for missing service")
+	}
+}
@@ -0,0 +1,109 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Action is the kind of change a plan makes to a resource.
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+func (a Action) symbol() string {
+	switch a {
+	case ActionCreate:
+		return "+"
+	case ActionDelete:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+// Change is a single planned modification.
+type Change struct {
+	Resource string
+	Name     string
+	Action   Action
+
+	// Before and After hold the current and desired values; Before is nil
+	// for creates and After is nil for deletes.
+	Before interface{}
+	After  interface{}
+
+	apply func(ctx context.Context) error
+}
+
+// String formats the change as a single line, e.g. `+ domain "cdn.example.com"`.
+func (c Change) String() string {
+	return fmt.Sprintf("%s %s %q", c.Action.symbol(), c.Resource, c.Name)
+}
+
+// Plan is the ordered list of changes needed to bring a service to the
+// desired state.
+type Plan struct {
+	ServiceID string
+	Changes   []Change
+}
+
+// Empty reports whether the service already matches the spec.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Summary counts the changes by action.
+func (p *Plan) Summary() (create, update, del int) {
+	for _, c := range p.Changes {
+		switch c.Action {
+		case ActionCreate:
+			create++
+		case ActionUpdate:
+			update++
+		case ActionDelete:
+			del++
+		}
+	}
+	return create, update, del
+}
+
+// String renders the plan as a human-readable diff.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return fmt.Sprintf("Service %s is up to date.\n", p.ServiceID)
+	}
+
+	var b strings.Builder
+	create, update, del := p.Summary()
+	fmt.Fprintf(&b, "Plan for service %s: %d to create, %d to update, %d to delete\n\n", p.ServiceID, create, update, del)
+	for _, c := range p.Changes {
+		fmt.Fprintf(&b, "  %s\n", c)
+		switch c.Action {
+		case ActionCreate:
+			fmt.Fprintf(&b, "      %s\n", formatValue(c.After))
+		case ActionUpdate:
+			fmt.Fprintf(&b, "      %s => %s\n", formatValue(c.Before), formatValue(c.After))
+		}
+	}
+	return b.String()
+}
+
+func formatValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		if strings.Contains(s, "\n") {
+			return fmt.Sprintf("(%d lines)", strings.Count(strings.TrimRight(s, "\n"), "\n")+1)
+		}
+		return fmt.Sprintf("%q", s)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,117 @@
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+)
+
+// ServiceSpec is the desired state of a single service. Sections that are
+// left out (nil) are not managed and never changed; an empty list means
+// "none", so every existing item of that section is removed.
+type ServiceSpec struct {
+	// ServiceID identifies the service. When empty, the service is looked up
+	// by UniqueName.
+	ServiceID  string `json:"serviceId,omitempty"`
+	UniqueName string `json:"uniqueName,omitempty"`
+
+	Domains []DomainSpec `json:"domains,omitempty"`
+
+	// Options lists the options to manage. Options that are not listed keep
+	// their current value.
+	Options api.ServiceOptions `json:"options,omitempty"`
+
+	RefererRules []RefererRuleSpec `json:"refererRules,omitempty"`
+
+	// Rules replaces the full rule set of the service. Each rule is sent as
+	// is; see ServiceRulesService.GetSchema for the accepted fields.
+	Rules []map[string]interface{} `json:"rules,omitempty"`
+
+	ImageOptimization *ImageOptimizationSpec `json:"imageOptimization,omitempty"`
+
+	Logging *LoggingSpec `json:"logging,omitempty"`
+}
+
+// DomainSpec is a domain attached to the service, matched by name.
+type DomainSpec struct {
+	Name           string `json:"name"`
+	Description    string `json:"description,omitempty"`
+	ValidationMode string `json:"validationMode,omitempty"`
+}
+
+// RefererRuleSpec is a referer rule, matched by directory and extension.
+type RefererRuleSpec struct {
+	Directory     string   `json:"directory"`
+	Extension     string   `json:"extension,omitempty"`
+	Exceptions    []string `json:"exceptions"`
+	DefaultAction string   `json:"defaultAction"`
+}
+
+// ImageOptimizationSpec is the image optimization configuration document.
+// An empty Config removes the configuration.
+type ImageOptimizationSpec struct {
+	Config string `json:"config"`
+}
+
+// LoggingSpec selects the log targets receiving the service's logs, by log
+// target ID or name. An empty value disables that kind of logging.
+type LoggingSpec struct {
+	AccessLogTarget string `json:"accessLogTarget"`
+	OriginLogTarget string `json:"originLogTarget"`
+}
+
+// LoadSpec decodes a JSON service spec. Unknown fields are rejected so typos
+// do not silently leave settings unmanaged.
+func LoadSpec(r io.Reader) (*ServiceSpec, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var spec ServiceSpec
+	if err := dec.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("failed to decode service spec: %w", err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// Validate checks the spec for missing or duplicate entries.
+func (s ServiceSpec) Validate() error {
+	if s.ServiceID == "" && s.UniqueName == "" {
+		return fmt.Errorf("serviceId or uniqueName is required")
+	}
+
+	seen := map[string]bool{}
+	for _, d := range s.Domains {
+		if d.Name == "" {
+			return fmt.Errorf("domain name is required")
+		}
+		if seen[d.Name] {
+			return fmt.Errorf("duplicate domain %q", d.Name)
+		}
+		seen[d.Name] = true
+	}
+
+	seen = map[string]bool{}
+	for _, r := range s.RefererRules {
+		if r.Directory == "" || r.DefaultAction == "" {
+			return fmt.Errorf("referer rule directory and defaultAction are required")
+		}
+		key := refererRuleKey(r.Directory, r.Extension)
+		if seen[key] {
+			return fmt.Errorf("duplicate referer rule %q", key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+func refererRuleKey(directory, extension string) string {
+	if extension == "" {
+		return directory
+	}
+	return directory + " " + extension
+}
@@ -0,0 +1,34 @@
+package cachefly
+
+import "github.com/cachefly/cachefly-sdk-go/internal/httpclient"
+
+// CachePolicy sets how long the responses of each cacheable resource are
+// kept. See WithCache.
+type CachePolicy = httpclient.CachePolicy
+
+// CacheResource names a group of rarely-changing endpoints that can be cached.
+type CacheResource = httpclient.CacheResource
+
+// Cacheable resources.
+const (
+	CacheOptionsMetadata   = httpclient.CacheOptionsMetadata
+	CacheTLSProfiles       = httpclient.CacheTLSProfiles
+	CacheDeliveryRegions   = httpclient.CacheDeliveryRegions
+	CacheScriptDefinitions = httpclient.CacheScriptDefinitions
+	CacheSchemas           = httpclient.CacheSchemas
+)
+
+// DefaultCachePolicy caches options metadata and schemas for 15 minutes, and
+// TLS profiles, delivery regions and script definitions for an hour.
+func DefaultCachePolicy() CachePolicy {
+	return httpclient.DefaultCachePolicy()
+}
+
+// InvalidateCache drops the cached responses of the given resources, or of
+// every resource when none is given. It does nothing when the client was
+// created without WithCache.
+func (c *Client) InvalidateCache(resources ...CacheResource) {
+	if c.cache != nil {
+		c.cache.Invalidate(resources...)
+	}
+}
@@ -0,0 +1,680 @@
+package cacheflytest
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+)
+
+// allPermissions are the permissions granted to the current user.
+var allPermissions = []string{"P_ADMIN_VIEW", "P_ADMIN_MANAGE", "P_ADMIN_BILLING", "P_ADMIN_STATS", "P_ACCOUNT_ADMIN"}
+
+// expiringWindow is how long before expiry a certificate is reported as expiring.
+const expiringWindow = 30 * 24 * time.Hour
+
+func (s *Server) routes(mux *http.ServeMux) {
+	// services
+	s.handle(mux, "GET /services", s.listServices)
+	s.handle(mux, "POST /services", s.createService)
+	s.handle(mux, "GET /services/{id}", s.getService)
+	s.handle(mux, "PUT /services/{id}", s.updateService)
+	s.handle(mux, "PUT /services/{id}/activate", s.setServiceStatus("ACTIVE"))
+	s.handle(mux, "PUT /services/{id}/deactivate", s.setServiceStatus("DEACTIVATED"))
+	s.handle(mux, "PUT /services/{id}/accessLogs", s.enableLogging("accessLogsServices"))
+	s.handle(mux, "DELETE /services/{id}/accessLogs", s.disableLogging("accessLogsServices"))
+	s.handle(mux, "PUT /services/{id}/originLogs", s.enableLogging("originLogsServices"))
+	s.handle(mux, "DELETE /services/{id}/originLogs", s.disableLogging("originLogsServices"))
+	s.handle(mux, "PUT /services/{id}/purge", s.purge)
+
+	// domains
+	s.handle(mux, "GET /services/{sid}/domains", s.listDomains)
+	s.handle(mux, "POST /services/{sid}/domains", s.createDomain)
+	s.handle(mux, "GET /services/{sid}/domains/{id}", s.getDomain)
+	s.handle(mux, "PUT /services/{sid}/domains/{id}", s.updateDomain)
+	s.handle(mux, "DELETE /services/{sid}/domains/{id}", s.deleteDomain)
+	s.handle(mux, "PUT /services/{sid}/domains/{id}/validationReady", s.domainValidationReady)
+
+	// options
+	s.handle(mux, "GET /services/{id}/options", s.getOptions)
+	s.handle(mux, "PUT /services/{id}/options", s.updateOptions)
+	s.handle(mux, "GET /services/{id}/options/metadata", s.getOptionsMetadata)
+	s.handle(mux, "GET /services/{id}/options/protectserve", s.getProtectServe)
+	s.handle(mux, "POST /services/{id}/options/protectserve", s.recreateProtectServe)
+	s.handle(mux, "PUT /services/{id}/options/protectserve", s.updateProtectServe)
+	s.handle(mux, "DELETE /services/{id}/options/protectserve", s.deleteProtectServe)
+	s.handle(mux, "GET /services/{id}/options/ftp", s.getFTP)
+	s.handle(mux, "POST /services/{id}/options/ftp", s.regenerateFTP)
+
+	// referer rules
+	s.handle(mux, "GET /services/{sid}/options/refererrules", s.listRefererRules)
+	s.handle(mux, "POST /services/{sid}/options/refererrules", s.createRefererRule)
+	s.handle(mux, "GET /services/{sid}/options/refererrules/{id}", s.getRefererRule)
+	s.handle(mux, "PUT /services/{sid}/options/refererrules/{id}", s.updateRefererRule)
+	s.handle(mux, "DELETE /services/{sid}/options/refererrules/{id}", s.deleteRefererRule)
+
+	// origins
+	s.handle(mux, "GET /origins", s.list(s.origins, "type"))
+	s.handle(mux, "POST /origins", s.create(s.origins, "type"))
+	s.handle(mux, "GET /origins/{id}", s.get(s.origins, "origin"))
+	s.handle(mux, "PUT /origins/{id}", s.update(s.origins, "origin"))
+	s.handle(mux, "DELETE /origins/{id}", s.remove(s.origins, "origin"))
+
+	// certificates
+	s.handle(mux, "GET /certificates", s.listCertificates)
+	s.handle(mux, "POST /certificates", s.createCertificate)
+	s.handle(mux, "GET /certificates/{id}", s.get(s.certificates, "certificate"))
+	s.handle(mux, "DELETE /certificates/{id}", s.remove(s.certificates, "certificate"))
+
+	// users
+	s.handle(mux, "GET /users/me", s.getCurrentUser)
+	s.handle(mux, "PUT /users/me", s.updateCurrentUser)
+	s.handle(mux, "PUT /users/me/enable2FA", s.setCurrentUser2FA(true))
+	s.handle(mux, "PUT /users/me/disable2FA", s.setCurrentUser2FA(false))
+	s.handle(mux, "GET /users", s.listUsers)
+	s.handle(mux, "POST /users", s.createUser)
+	s.handle(mux, "GET /users/{id}", s.get(s.users, "user"))
+	s.handle(mux, "PUT /users/{id}", s.updateUser)
+	s.handle(mux, "DELETE /users/{id}", s.remove(s.users, "user"))
+	s.handle(mux, "GET /users/{id}/allowedPermissions", s.allowedPermissions)
+	s.handle(mux, "PUT /users/{id}/activate", s.setUserStatus("ACTIVE"))
+	s.handle(mux, "PUT /users/{id}/deactivate", s.setUserStatus("DEACTIVATED"))
+
+	// log targets
+	s.handle(mux, "GET /logtargets", s.list(s.logTargets, "type"))
+	s.handle(mux, "POST /logtargets", s.create(s.logTargets, "type"))
+	s.handle(mux, "GET /logtargets/{id}", s.get(s.logTargets, "log target"))
+	s.handle(mux, "PUT /logtargets/{id}", s.update(s.logTargets, "log target"))
+	s.handle(mux, "DELETE /logtargets/{id}", s.remove(s.logTargets, "log target"))
+	s.handle(mux, "PUT /logtargets/{id}/logging", s.setLogTargetLogging)
+
+	// cache warming
+	s.handle(mux, "GET /cachewarming", s.list(s.warmingTasks, ""))
+	s.handle(mux, "POST /cachewarming", s.createWarmingTask)
+	s.handle(mux, "GET /cachewarming/{id}", s.get(s.warmingTasks, "cache warming task"))
+	s.handle(mux, "DELETE /cachewarming/{id}", s.remove(s.warmingTasks, "cache warming task"))
+
+	// delivery regions
+	s.handle(mux, "GET /deliveryregions", s.list(s.regions, ""))
+}
+
+// Generic handlers
+
+// list serves a collection, filtered by the query parameter filter when set.
+func (s *Server) list(c *collection, filter string) handler {
+	return func(r *http.Request) (int, interface{}) {
+		if filter == "" {
+			return page(r, c.list())
+		}
+		return page(r, c.list(fieldEquals(filter, r.URL.Query().Get(filter))))
+	}
+}
+
+// create stores the request body in c after checking the required fields.
+func (s *Server) create(c *collection, fields ...string) handler {
+	return func(r *http.Request) (int, interface{}) {
+		body, err := decode(r)
+		if err != nil {
+			return errorf(http.StatusBadRequest, "invalid JSON: %v", err)
+		}
+		if missing := required(body, fields...); len(missing) > 0 {
+			return invalid(missing...)
+		}
+		delete(body, "_id")
+		return http.StatusCreated, s.insert(c, body)
+	}
+}
+
+func (s *Server) get(c *collection, kind string) handler {
+	return func(r *http.Request) (int, interface{}) {
+		d, ok := c.get(r.PathValue("id"))
+		if !ok {
+			return notFound(kind, r.PathValue("id"))
+		}
+		return http.StatusOK, d
+	}
+}
+
+func (s *Server) update(c *collection, kind string) handler {
+	return func(r *http.Request) (int, interface{}) {
+		d, ok := c.get(r.PathValue("id"))
+		if !ok {
+			return notFound(kind, r.PathValue("id"))
+		}
+		body, err := decode(r)
+		if err != nil {
+			return errorf(http.StatusBadRequest, "invalid JSON: %v", err)
+		}
+		merge(d, body)
+		return http.StatusOK, d
+	}
+}
+
+func (s *Server) remove(c *collection, kind string) handler {
+	return func(r *http.Request) (int, interface{}) {
+		if !c.delete(r.PathValue("id")) {
+			return notFound(kind, r.PathValue("id"))
+		}
+		return http.StatusNoContent, nil
+	}
+}
+
+// Services
+
+func (s *Server) listServices(r *http.Request) (int, interface{}) {
+	return page(r, s.services.list(fieldEquals("status", r.URL.Query().Get("status"))))
+}
+
+func (s *Server) createService(r *http.Request) (int, interface{}) {
+	body, err := decode(r)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "invalid JSON: %v", err)
+	}
+	if missing := required(body, "name", "uniqueName"); len(missing) > 0 {
+		return invalid(missing...)
+	}
+	if len(s.services.list(fieldEquals("uniqueName", str(body, "uniqueName")))) > 0 {
+		return errorf(http.StatusConflict, "service with uniqueName %s already exists", str(body, "uniqueName"))
+	}
+	svc := s.insertService(doc{
+		"name":        body["name"],
+		"uniqueName":  body["uniqueName"],
+		"description": str(body, "description"),
+	})
+	return http.StatusCreated, svc
+}
+
+// insertService stores a service with the API defaults and empty options.
+func (s *Server) insertService(d doc) doc {
+	if str(d, "status") == "" {
+		d["status"] = "ACTIVE"
+	}
+	if str(d, "configurationMode") == "" {
+		d["configurationMode"] = "API_RULES_AND_OPTIONS"
+	}
+	if _, ok := d["autoSsl"]; !ok {
+		d["autoSsl"] = false
+	}
+	d = s.insert(s.services, d)
+	if _, ok := s.options[str(d, "_id")]; !ok {
+		s.options[str(d, "_id")] = doc{}
+	}
+	return d
+}
+
+// service looks up the service named by the id or sid path value.
+func (s *Server) service(r *http.Request) (doc, bool) {
+	id := r.PathValue("sid")
+	if id == "" {
+		id = r.PathValue("id")
+	}
+	return s.services.get(id)
+}
+
+func (s *Server) getService(r *http.Request) (int, interface{}) {
+	svc, ok := s.service(r)
+	if !ok {
+		return notFound("service", r.PathValue("id"))
+	}
+	return http.StatusOK, svc
+}
+
+func (s *Server) updateService(r *http.Request) (int, interface{}) {
+	svc, ok := s.service(r)
+	if !ok {
+		return notFound("service", r.PathValue("id"))
+	}
+	body, err := decode(r)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "invalid JSON: %v", err)
+	}
+	patch := doc{}
+	for _, k := range []string{"description", "tlsProfile", "autoSsl", "deliveryRegion"} {
+		if v, ok := body[k]; ok {
+			patch[k] = v
+		}
+	}
+	merge(svc, patch)
+	return http.StatusOK, svc
+}
+
+func (s *Server) setServiceStatus(status string) handler {
+	return func(r *http.Request) (int, interface{}) {
+		svc, ok := s.service(r)
+		if !ok {
+			return notFound("service", r.PathValue("id"))
+		}
+		merge(svc, doc{"status": status})
+		return http.StatusOK, svc
+	}
+}
+
+// enableLogging attaches the service to the log target in the request body
+// by adding it to the target's field list, detaching it from any other.
+func (s *Server) enableLogging(field string) handler {
+	return func(r *http.Request) (int, interface{}) {
+		svc, ok := s.service(r)
+		if !ok {
+			return notFound("service", r.PathValue("id"))
+		}
+		body, err := decode(r)
+		if err != nil {
+			return errorf(http.StatusBadRequest, "invalid JSON: %v", err)
+		}
+		if missing := required(body, "logTarget"); len(missing) > 0 {
+			return invalid(missing...)
+		}
+		target, ok := s.logTargets.get(str(body, "logTarget"))
+		if !ok {
+			return notFound("log target", str(body, "logTarget"))
+		}
+		sid := str(svc, "_id")
+		s.detachLogging(field, sid)
+		target[field] = append(strs(target, field), sid)
+		return http.StatusOK, svc
+	}
+}
+
+func (s *Server) disableLogging(field string) handler {
+	return func(r *http.Request) (int, interface{}) {
+		svc, ok := s.service(r)
+		if !ok {
+			return notFound("service", r.PathValue("id"))
+		}
+		s.detachLogging(field, str(svc, "_id"))
+		return http.StatusOK, svc
+	}
+}
+
+func (s *Server) detachLogging(field, sid string) {
+	for _, t := range s.logTargets.list() {
+		if list := strs(t, field); slices.Contains(list, sid) {
+			t[field] = slices.DeleteFunc(list, func(v string) bool { return v == sid })
+		}
+	}
+}
+
+func (s *Server) purge(r *http.Request) (int, interface{}) {
+	svc, ok := s.service(r)
+	if !ok {
+		return notFound("service", r.PathValue("id"))
+	}
+	body, err := decode(r)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "invalid JSON: %v", err)
+	}
+	req := fromDoc[api.PurgeRequest](body)
+	if !req.All && len(req.Paths) == 0 {
+		return errorf(http.StatusBadRequest, "either all or paths must be provided")
+	}
+	sid := str(svc, "_id")
+	s.purges[sid] = append(s.purges[sid], req)
+	return http.StatusOK, doc{}
+}
+
+// Domains
+
+func (s *Server) listDomains(r *http.Request) (int, interface{}) {
+	if _, ok := s.service(r); !ok {
+		return notFound("service", r.PathValue("sid"))
+	}
+	return page(r, s.domains.list(
+		fieldEquals("service", r.PathValue("sid")),
+		fieldContains(r.URL.Query().Get("search"), "name", "description"),
+	))
+}
+
+func (s *Server) createDomain(r *http.Request) (int, interface{}) {
+	if _, ok := s.service(r); !ok {
+		return notFound("service", r.PathValue("sid"))
+	}
+	body, err := decode(r)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "invalid JSON: %v", err)
+	}
+	if missing := required(body, "name"); len(missing) > 0 {
+		return invalid(missing...)
+	}
+	if s.domainExists(r.PathValue("sid"), str(body, "name")) {
+		return errorf(http.StatusConflict, "domain %s already exists", str(body, "name"))
+	}
+	delete(body, "_id")
+	body["service"] = r.PathValue("sid")
+	return http.StatusCreated, s.insertDomain(body)
+}
+
+// insertDomain stores a domain with the API's validation defaults.
+func (s *Server) insertDomain(d doc) doc {
+	if str(d, "validationMode") == "" {
+		d["validationMode"] = api.DomainValidationModeHTTP
+	}
+	if str(d, "validationStatus") == "" {
+		d["validationStatus"] = string(api.DomainValidationPending)
+	}
+	if str(d, "validationTarget") == "" {
+		d["validationTarget"] = strings.ReplaceAll(strings.ToLower(str(d, "name")), ".", "-") + ".validation.cachefly.test"
+	}
+	if _, ok := d["certificates"]; !ok {
+		d["certificates"] = []string{}
+	}
+	return s.insert(s.domains, d)
+}
+
+func (s *Server) domainExists(sid, name string) bool {
+	return len(s.domains.list(fieldEquals("service", sid), func(d doc) bool {
+		return strings.EqualFold(str(d, "name"), name)
+	})) > 0
+}
+
+// domain looks up a domain of the service in the request path.
+func (s *Server) domain(r *http.Request) (doc, bool) {
+	d, ok := s.domains.get(r.PathValue("id"))
+	if !ok || str(d, "service") != r.PathValue("sid") {
+		return nil, false
+	}
+	return d, true
+}
+
+func (s *Server) getDomain(r *http.Request) (int, interface{}) {
+	d, ok := s.domain(r)
+	if !ok {
+		return notFound("domain", r.PathValue("id"))
+	}
+	return http.StatusOK, d
+}
+
+func (s *Server) updateDomain(r *http.Request) (int, interface{}) {
+	d, ok := s.domain(r)
+	if !ok {
+		return notFound("domain", r.PathValue("id"))
+	}
+	body, err := decode(r)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "invalid JSON: %v", err)
+	}
+	if name := str(body, "name"); name != "" && !strings.EqualFold(name, str(d, "name")) && s.domainExists(r.PathValue("sid"), name) {
+		return errorf(http.StatusConflict, "domain %s already exists", name)
+	}
+	delete(body, "service")
+	merge(d, body)
+	return http.StatusOK, d
+}
+
+func (s *Server) deleteDomain(r *http.Request) (int, interface{}) {
+	if _, ok := s.domain(r); !ok {
+		return notFound("domain", r.PathValue("id"))
+	}
+	s.domains.delete(r.PathValue("id"))
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) domainValidationReady(r *http.Request) (int, interface{}) {
+	d, ok := s.domain(r)
+	if !ok {
+		return notFound("domain", r.PathValue("id"))
+	}
+	merge(d, doc{"validationStatus": string(api.DomainValidationValidating)})
+	return http.StatusOK, d
+}
+
+// Referer rules
+
+func (s *Server) refererRuleCollection(sid string) *collection {
+	c, ok := s.refererRules[sid]
+	if !ok {
+		c = newCollection()
+		s.refererRules[sid] = c
+	}
+	return c
+}
+
+func (s *Server) listRefererRules(r *http.Request) (int, interface{}) {
+	if _, ok := s.service(r); !ok {
+		return notFound("service", r.PathValue("sid"))
+	}
+	return page(r, s.refererRuleCollection(r.PathValue("sid")).list())
+}
+
+func (s *Server) createRefererRule(r *http.Request) (int, interface{}) {
+	if _, ok := s.service(r); !ok {
+		return notFound("service", r.PathValue("sid"))
+	}
+	body, err := decode(r)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "invalid JSON: %v", err)
+	}
+	if missing := required(body, "directory", "defaultAction"); len(missing) > 0 {
+		return invalid(missing...)
+	}
+	delete(body, "_id")
+	return http.StatusCreated, s.insertRefererRule(r.PathValue("sid"), body)
+}
+
+// insertRefererRule appends a rule to the service's rules, ordering it last.
+func (s *Server) insertRefererRule(sid string, d doc) doc {
+	c := s.refererRuleCollection(sid)
+	if order, _ := d["order"].(float64); order == 0 {
+		d["order"] = len(c.order) + 1
+	}
+	if _, ok := d["exceptions"]; !ok {
+		d["exceptions"] = []string{}
+	}
+	return s.insert(c, d)
+}
+
+func (s *Server) getRefererRule(r *http.Request) (int, interface{}) {
+	d, ok := s.refererRuleCollection(r.PathValue("sid")).get(r.PathValue("id"))
+	if !ok {
+		return notFound("referer rule", r.PathValue("id"))
+	}
+	return http.StatusOK, d
+}
+
+func (s *Server) updateRefererRule(r *http.Request) (int, interface{}) {
+	return s.update(s.refererRuleCollection(r.PathValue("sid")), "referer rule")(r)
+}
+
+func (s *Server) deleteRefererRule(r *http.Request) (int, interface{}) {
+	return s.remove(s.refererRuleCollection(r.PathValue("sid")), "referer rule")(r)
+}
+
+// Certificates
+
+func (s *Server) listCertificates(r *http.Request) (int, interface{}) {
+	search := r.URL.Query().Get("search")
+	return page(r, s.certificates.list(func(d doc) bool {
+		if fieldContains(search, "subjectCommonName")(d) {
+			return true
+		}
+		return slices.ContainsFunc(strs(d, "subjectNames"), func(n string) bool {
+			return strings.Contains(strings.ToLower(n), strings.ToLower(search))
+		})
+	}))
+}
+
+func (s *Server) createCertificate(r *http.Request) (int, interface{}) {
+	body, err := decode(r)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "invalid JSON: %v", err)
+	}
+	if missing := required(body, "certificate", "certificateKey"); len(missing) > 0 {
+		return invalid(missing...)
+	}
+	block, _ := pem.Decode([]byte(str(body, "certificate")))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return errorf(http.StatusBadRequest, "certificate is not a PEM encoded certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "invalid certificate: %v", err)
+	}
+	if key, _ := pem.Decode([]byte(str(body, "certificateKey"))); key == nil {
+		return errorf(http.StatusBadRequest, "certificateKey is not a PEM encoded private key")
+	}
+
+	names := cert.DNSNames
+	if names == nil {
+		names = []string{}
+	}
+	d := s.insert(s.certificates, doc{
+		"subjectCommonName": cert.Subject.CommonName,
+		"subjectNames":      names,
+		"notBefore":         cert.NotBefore.UTC().Format(time.RFC3339),
+		"notAfter":          cert.NotAfter.UTC().Format(time.RFC3339),
+		"inUse":             false,
+		"managed":           false,
+		"services":          []string{},
+		"domains":           []string{},
+	})
+	setExpiry(d, time.Now())
+	return http.StatusCreated, d
+}
+
+// setExpiry derives the expired and expiring flags of a certificate.
+func setExpiry(d doc, now time.Time) {
+	notAfter, err := time.Parse(time.RFC3339, str(d, "notAfter"))
+	if err != nil {
+		return
+	}
+	d["expired"] = now.After(notAfter)
+	d["expiring"] = !now.After(notAfter) && notAfter.Sub(now) < expiringWindow
+}
+
+// Users
+
+func (s *Server) listUsers(r *http.Request) (int, interface{}) {
+	return page(r, s.users.list(fieldContains(r.URL.Query().Get("search"), "username", "email", "fullName")))
+}
+
+func (s *Server) createUser(r *http.Request) (int, interface{}) {
+	body, err := decode(r)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "invalid JSON: %v", err)
+	}
+	if missing := required(body, "username", "password"); len(missing) > 0 {
+		return invalid(missing...)
+	}
+	if len(s.users.list(fieldEquals("username", str(body, "username")))) > 0 {
+		return errorf(http.StatusConflict, "user %s already exists", str(body, "username"))
+	}
+	delete(body, "_id")
+	delete(body, "password")
+	return http.StatusCreated, s.insertUser(body)
+}
+
+// insertUser stores a user with the API defaults.
+func (s *Server) insertUser(d doc) doc {
+	if str(d, "status") == "" {
+		d["status"] = "ACTIVE"
+	}
+	for _, k := range []string{"permissions", "services"} {
+		if d[k] == nil {
+			d[k] = []string{}
+		}
+	}
+	return s.insert(s.users, d)
+}
+
+func (s *Server) updateUser(r *http.Request) (int, interface{}) {
+	d, ok := s.users.get(r.PathValue("id"))
+	if !ok {
+		return notFound("user", r.PathValue("id"))
+	}
+	body, err := decode(r)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "invalid JSON: %v", err)
+	}
+	delete(body, "password")
+	delete(body, "username")
+	merge(d, body)
+	return http.StatusOK, d
+}
+
+func (s *Server) currentUser() doc {
+	d, _ := s.users.get(s.currentUserID)
+	return d
+}
+
+func (s *Server) getCurrentUser(r *http.Request) (int, interface{}) {
+	return http.StatusOK, s.currentUser()
+}
+
+func (s *Server) updateCurrentUser(r *http.Request) (int, interface{}) {
+	r.SetPathValue("id", s.currentUserID)
+	return s.updateUser(r)
+}
+
+func (s *Server) setCurrentUser2FA(enabled bool) handler {
+	return func(r *http.Request) (int, interface{}) {
+		d := s.currentUser()
+		merge(d, doc{"twoFactorAuthEnabled": enabled})
+		return http.StatusOK, d
+	}
+}
+
+func (s *Server) setUserStatus(status string) handler {
+	return func(r *http.Request) (int, interface{}) {
+		d, ok := s.users.get(r.PathValue("id"))
+		if !ok {
+			return notFound("user", r.PathValue("id"))
+		}
+		merge(d, doc{"status": status})
+		return http.StatusOK, d
+	}
+}
+
+func (s *Server) allowedPermissions(r *http.Request) (int, interface{}) {
+	if _, ok := s.users.get(r.PathValue("id")); !ok {
+		return notFound("user", r.PathValue("id"))
+	}
+	return http.StatusOK, doc{"permissions": allPermissions}
+}
+
+// Log targets
+
+func (s *Server) setLogTargetLogging(r *http.Request) (int, interface{}) {
+	d, ok := s.logTargets.get(r.PathValue("id"))
+	if !ok {
+		return notFound("log target", r.PathValue("id"))
+	}
+	body, err := decode(r)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "invalid JSON: %v", err)
+	}
+	req := fromDoc[api.SetLoggingRequest](body)
+	for _, sid := range slices.Concat(req.AccessLogsServices, req.OriginLogsServices) {
+		if _, ok := s.services.get(sid); !ok {
+			return notFound("service", sid)
+		}
+	}
+	for field, sids := range map[string][]string{"accessLogsServices": req.AccessLogsServices, "originLogsServices": req.OriginLogsServices} {
+		for _, sid := range sids {
+			s.detachLogging(field, sid)
+		}
+		if sids == nil {
+			sids = []string{}
+		}
+		d[field] = sids
+	}
+	merge(d, doc{})
+	return http.StatusOK, d
+}
+
+// Cache warming
+
+func (s *Server) createWarmingTask(r *http.Request) (int, interface{}) {
+	body, err := decode(r)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "invalid JSON: %v", err)
+	}
+	if missing := required(body, "targets"); len(missing) > 0 {
+		return invalid(missing...)
+	}
+	body["status"] = "PENDING"
+	delete(body, "_id")
+	return http.StatusCreated, s.insert(s.warmingTasks, body)
+}
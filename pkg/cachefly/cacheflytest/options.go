@@ -0,0 +1,177 @@
+package cacheflytest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+)
+
+// DefaultOptionsMetadata returns the option metadata served by a new Server:
+// every standard option and a few dynamic ones.
+func DefaultOptionsMetadata() []api.OptionMetadata {
+	intPtr := func(v int) *int { return &v }
+	meta := []api.OptionMetadata{
+		{Name: "CORS Override", Title: "CORS", Type: "standard", Group: "Headers"},
+		{Name: "Auto HTTPS Redirect", Title: "Auto HTTPS Redirect", Type: "standard", Group: "Security"},
+		{Name: "Referrer Blocking", Title: "Referrer Blocking", Type: "standard", Group: "Security"},
+		{Name: "ProtectServe", Title: "ProtectServe", Type: "standard", Group: "Security"},
+		{Name: "Reverse Proxy", Title: "Reverse Proxy", Type: "standard", Group: "Origin"},
+		{Name: "Expiry Overrides", Title: "Expiry Overrides", Type: "standard", Group: "Caching"},
+		{Name: "Error TTL", Title: "Error TTL", Type: "dynamic", Group: "Caching", Property: &api.OptionProperty{
+			Name: "error_ttl", Label: "Seconds", Type: "integer", MinValue: intPtr(0), MaxValue: intPtr(31536000),
+		}},
+		{Name: "Send X-Forwarded-For", Title: "Send X-Forwarded-For", Type: "dynamic", Group: "Origin", Property: &api.OptionProperty{
+			Name: "sendxff", Label: "Enabled", Type: "boolean",
+		}},
+		{Name: "Brotli Compression", Title: "Brotli Compression", Type: "dynamic", Group: "Delivery", Property: &api.OptionProperty{
+			Name: "brotli_support", Label: "Enabled", Type: "boolean",
+		}},
+		{Name: "Edge to Origin", Title: "Edge to Origin", Type: "dynamic", Group: "Origin", ReadOnly: true, Property: &api.OptionProperty{
+			Name: "edgetoorigin", Label: "Enabled", Type: "boolean",
+		}},
+	}
+	for i := range meta {
+		meta[i].ID = "meta-" + strings.ToLower(strings.ReplaceAll(meta[i].Name, " ", "-"))
+	}
+	return meta
+}
+
+func (s *Server) getOptions(r *http.Request) (int, interface{}) {
+	svc, ok := s.service(r)
+	if !ok {
+		return notFound("service", r.PathValue("id"))
+	}
+	return http.StatusOK, s.options[str(svc, "_id")]
+}
+
+// updateOptions merges the request into the service options, rejecting keys
+// the metadata does not know and read-only options with 422.
+func (s *Server) updateOptions(r *http.Request) (int, interface{}) {
+	svc, ok := s.service(r)
+	if !ok {
+		return notFound("service", r.PathValue("id"))
+	}
+	body, err := decode(r)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "invalid JSON: %v", err)
+	}
+
+	registry := api.NewOptionRegistry(&api.ServiceOptionsMetadata{Data: s.metadata})
+	var errs []map[string]string
+	for k := range body {
+		m, ok := registry.Lookup(k)
+		switch {
+		case !ok:
+			errs = append(errs, map[string]string{"field": k, "message": "option is not available"})
+		case m.ReadOnly:
+			errs = append(errs, map[string]string{"field": k, "message": "option is read-only"})
+		}
+	}
+	if len(errs) > 0 {
+		return http.StatusUnprocessableEntity, map[string]interface{}{
+			"message": "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"errors":  errs,
+		}
+	}
+
+	opts := s.options[str(svc, "_id")]
+	for k, v := range body {
+		opts[k] = v
+	}
+	return http.StatusOK, opts
+}
+
+func (s *Server) getOptionsMetadata(r *http.Request) (int, interface{}) {
+	if _, ok := s.service(r); !ok {
+		return notFound("service", r.PathValue("id"))
+	}
+	var res api.ServiceOptionsMetadata
+	res.Meta.Count = len(s.metadata)
+	res.Data = s.metadata
+	return http.StatusOK, res
+}
+
+func (s *Server) getProtectServe(r *http.Request) (int, interface{}) {
+	if _, ok := s.service(r); !ok {
+		return notFound("service", r.PathValue("id"))
+	}
+	ps, ok := s.protectServe[r.PathValue("id")]
+	if !ok {
+		return notFound("protectserve key for service", r.PathValue("id"))
+	}
+	res := *ps
+	if r.URL.Query().Get("hideSecrets") == "true" {
+		res.ProtectServeKey = strings.Repeat("*", len(res.ProtectServeKey))
+	}
+	return http.StatusOK, res
+}
+
+func (s *Server) recreateProtectServe(r *http.Request) (int, interface{}) {
+	if _, ok := s.service(r); !ok {
+		return notFound("service", r.PathValue("id"))
+	}
+	sid := r.PathValue("id")
+	ps, ok := s.protectServe[sid]
+	if !ok {
+		ps = &api.ProtectServeKeyResponse{ForceProtectServe: "DISABLED"}
+		s.protectServe[sid] = ps
+	}
+	ps.ProtectServeKey = randomHex(16)
+	return http.StatusOK, ps
+}
+
+func (s *Server) updateProtectServe(r *http.Request) (int, interface{}) {
+	if _, ok := s.service(r); !ok {
+		return notFound("service", r.PathValue("id"))
+	}
+	ps, ok := s.protectServe[r.PathValue("id")]
+	if !ok {
+		return notFound("protectserve key for service", r.PathValue("id"))
+	}
+	body, err := decode(r)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "invalid JSON: %v", err)
+	}
+	req := fromDoc[api.UpdateProtectServeRequest](body)
+	if req.ForceProtectServe != "" {
+		ps.ForceProtectServe = req.ForceProtectServe
+	}
+	if req.ProtectServeKey != "" {
+		ps.ProtectServeKey = req.ProtectServeKey
+	}
+	return http.StatusOK, ps
+}
+
+func (s *Server) deleteProtectServe(r *http.Request) (int, interface{}) {
+	if _, ok := s.service(r); !ok {
+		return notFound("service", r.PathValue("id"))
+	}
+	sid := r.PathValue("id")
+	delete(s.protectServe, sid)
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) getFTP(r *http.Request) (int, interface{}) {
+	if _, ok := s.service(r); !ok {
+		return notFound("service", r.PathValue("id"))
+	}
+	return http.StatusOK, api.FTPSettingsResponse{FTPPassword: s.ftpPasswords[r.PathValue("id")]}
+}
+
+func (s *Server) regenerateFTP(r *http.Request) (int, interface{}) {
+	if _, ok := s.service(r); !ok {
+		return notFound("service", r.PathValue("id"))
+	}
+	s.ftpPasswords[r.PathValue("id")] = randomHex(12)
+	return http.StatusOK, api.FTPSettingsResponse{FTPPassword: s.ftpPasswords[r.PathValue("id")]}
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
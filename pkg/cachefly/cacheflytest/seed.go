@@ -0,0 +1,142 @@
+package cacheflytest
+
+import (
+	"time"
+
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+)
+
+// AddService stores a service as if it had been created through the API and
+// returns it with its ID and defaults filled in.
+func (s *Server) AddService(svc api.Service) api.Service {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fromDoc[api.Service](s.insertService(toDoc(svc)))
+}
+
+// AddDomain stores a domain of service sid.
+func (s *Server) AddDomain(sid string, d api.ServiceDomain) api.ServiceDomain {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d.Service = sid
+	return fromDoc[api.ServiceDomain](s.insertDomain(toDoc(d)))
+}
+
+// SetDomainValidationStatus changes the validation status of a domain, e.g.
+// to complete a validation started with ValidationReady. It reports whether
+// the domain exists.
+func (s *Server) SetDomainValidationStatus(id string, status api.DomainValidationStatus) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.domains.get(id)
+	if !ok {
+		return false
+	}
+	merge(d, doc{"validationStatus": string(status)})
+	return true
+}
+
+// AddOrigin stores an origin.
+func (s *Server) AddOrigin(o api.Origin) api.Origin {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fromDoc[api.Origin](s.insert(s.origins, toDoc(o)))
+}
+
+// AddCertificate stores certificate metadata. The expired and expiring flags
+// are derived from NotAfter when it is set.
+func (s *Server) AddCertificate(c api.Certificate) api.Certificate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.insert(s.certificates, toDoc(c))
+	setExpiry(d, time.Now())
+	return fromDoc[api.Certificate](d)
+}
+
+// AddUser stores a user.
+func (s *Server) AddUser(u api.User) api.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fromDoc[api.User](s.insertUser(toDoc(u)))
+}
+
+// AddLogTarget stores a log target.
+func (s *Server) AddLogTarget(t api.LogTarget) api.LogTarget {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fromDoc[api.LogTarget](s.insert(s.logTargets, toDoc(t)))
+}
+
+// AddCacheWarmingTask stores a cache warming task. Status defaults to PENDING.
+func (s *Server) AddCacheWarmingTask(t api.CacheWarmingTask) api.CacheWarmingTask {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t.Status == "" {
+		t.Status = string(api.CacheWarmingPending)
+	}
+	return fromDoc[api.CacheWarmingTask](s.insert(s.warmingTasks, toDoc(t)))
+}
+
+// AddDeliveryRegion stores a delivery region.
+func (s *Server) AddDeliveryRegion(r api.DeliveryRegion) api.DeliveryRegion {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fromDoc[api.DeliveryRegion](s.insert(s.regions, toDoc(r)))
+}
+
+// SetCacheWarmingStatus changes the status of a cache warming task, setting
+// startedAt when it starts running and stoppedAt once it is done. It reports
+// whether the task exists.
+func (s *Server) SetCacheWarmingStatus(id string, status api.CacheWarmingStatus) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.warmingTasks.get(id)
+	if !ok {
+		return false
+	}
+	patch := doc{"status": string(status)}
+	if str(d, "startedAt") == "" && status != api.CacheWarmingPending {
+		patch["startedAt"] = now()
+	}
+	if status.Done() {
+		patch["stoppedAt"] = now()
+	}
+	merge(d, patch)
+	return true
+}
+
+// AddRefererRule stores a referer rule of service sid.
+func (s *Server) AddRefererRule(sid string, rule api.RefererRule) api.RefererRule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fromDoc[api.RefererRule](s.insertRefererRule(sid, toDoc(rule)))
+}
+
+// SetOptions replaces the options of service sid.
+func (s *Server) SetOptions(sid string, opts api.ServiceOptions) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.options[sid] = toDoc(opts)
+}
+
+// Options returns a copy of the current options of service sid.
+func (s *Server) Options(sid string) api.ServiceOptions {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fromDoc[api.ServiceOptions](s.options[sid])
+}
+
+// SetOptionsMetadata replaces the option metadata served for every service
+// and used to validate option updates.
+func (s *Server) SetOptionsMetadata(meta []api.OptionMetadata) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metadata = append([]api.OptionMetadata(nil), meta...)
+}
+
+// Purges returns the purge requests received for service sid, oldest first.
+func (s *Server) Purges(sid string) []api.PurgeRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]api.PurgeRequest(nil), s.purges[sid]...)
+}
@@ -0,0 +1,307 @@
+// Package cacheflytest provides an in-memory fake of the CacheFly API for
+// tests.
+//
+// A Server keeps services, domains, origins, certificates, users, log
+// targets, service options, referer rules, purges and cache warming tasks in
+// memory and serves them over HTTP with the same paths, pagination envelope
+// and error status codes as the real API, so a cachefly.Client pointed at it
+// behaves as it would in production:
+//
+//	srv := cacheflytest.NewServer()
+//	defer srv.Close()
+//
+//	client := srv.Client()
+//	svc, err := client.Services.Create(ctx, api.CreateServiceRequest{Name: "web", UniqueName: "web-1"})
+//
+// Tests can seed state with the Add methods, inspect what the code under test
+// did with Purges, Options and Requests, and simulate outages with FailNext.
+package cacheflytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+)
+
+// BasePath is the API path prefix served by the fake.
+const BasePath = "/api/2.6"
+
+// DefaultToken is the bearer token accepted by a new Server.
+const DefaultToken = "test-token"
+
+// defaultLimit is the page size used when a list request sets no limit.
+const defaultLimit = 10
+
+// Server is an in-memory fake of the CacheFly API.
+type Server struct {
+	// URL is the API base URL, including BasePath.
+	URL string
+
+	// Token is the bearer token every request must carry. When empty any
+	// non-empty token is accepted.
+	Token string
+
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	nextID   int
+	requests []string
+	failures []failure
+
+	services      *collection
+	domains       *collection
+	origins       *collection
+	certificates  *collection
+	users         *collection
+	logTargets    *collection
+	warmingTasks  *collection
+	regions       *collection
+	refererRules  map[string]*collection
+	options       map[string]doc
+	protectServe  map[string]*api.ProtectServeKeyResponse
+	ftpPasswords  map[string]string
+	purges        map[string][]api.PurgeRequest
+	metadata      []api.OptionMetadata
+	currentUserID string
+}
+
+// failure is a response injected with FailNext.
+type failure struct {
+	method string
+	path   string
+	status int
+}
+
+// NewServer starts a fake API server with no resources other than the
+// current user. Close it when the test is done.
+func NewServer() *Server {
+	s := &Server{
+		Token:        DefaultToken,
+		services:     newCollection(),
+		domains:      newCollection(),
+		origins:      newCollection(),
+		certificates: newCollection(),
+		users:        newCollection(),
+		logTargets:   newCollection(),
+		warmingTasks: newCollection(),
+		regions:      newCollection(),
+		refererRules: map[string]*collection{},
+		options:      map[string]doc{},
+		protectServe: map[string]*api.ProtectServeKeyResponse{},
+		ftpPasswords: map[string]string{},
+		purges:       map[string][]api.PurgeRequest{},
+		metadata:     DefaultOptionsMetadata(),
+	}
+	me := s.AddUser(api.User{Username: "test", Email: "test@example.com", FullName: "Test User", Permissions: allPermissions})
+	s.currentUserID = me.ID
+
+	mux := http.NewServeMux()
+	s.routes(mux)
+	s.handle(mux, " /", func(r *http.Request) (int, interface{}) {
+		return errorf(http.StatusNotFound, "no route for %s %s", r.Method, r.URL.Path)
+	})
+	s.srv = httptest.NewServer(mux)
+	s.URL = s.srv.URL + BasePath
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client returns a cachefly.Client configured for the server. Additional
+// options are applied after the token and base URL.
+func (s *Server) Client(opts ...cachefly.Option) *cachefly.Client {
+	token := s.Token
+	if token == "" {
+		token = DefaultToken
+	}
+	opts = append([]cachefly.Option{cachefly.WithToken(token), cachefly.WithBaseURL(s.URL)}, opts...)
+	return cachefly.NewClient(opts...)
+}
+
+// FailNext makes the next request matching method and path (relative to
+// BasePath, without query string) fail with status. Failures are consumed in
+// the order they were added; 429 and 503 responses carry "Retry-After: 0".
+func (s *Server) FailNext(method, path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{method: method, path: path, status: status})
+}
+
+// Requests returns every request received so far as "METHOD /path", with the
+// path relative to BasePath.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// handler serves a request with the server lock held and returns the status
+// and the value to encode as the JSON response body.
+type handler func(r *http.Request) (int, interface{})
+
+// handle registers h for a "METHOD /path" pattern relative to BasePath. A
+// pattern without a method is registered as is and matches any method.
+func (s *Server) handle(mux *http.ServeMux, pattern string, h handler) {
+	method, path, _ := strings.Cut(pattern, " ")
+	if method != "" {
+		path = method + " " + BasePath + path
+	}
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		status, body := s.serve(r, h)
+		w.Header().Set("Content-Type", "application/json")
+		if status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+			w.Header().Set("Retry-After", "0")
+		}
+		w.WriteHeader(status)
+		if status != http.StatusNoContent {
+			w.Write(body)
+		}
+	})
+}
+
+// serve runs h under the server lock and encodes its response, so stored
+// documents are never read while another request modifies them.
+func (s *Server) serve(r *http.Request, h handler) (int, []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status, body := s.dispatch(r, h)
+	if body == nil {
+		return status, nil
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		b, _ = json.Marshal(map[string]string{"message": err.Error()})
+		return http.StatusInternalServerError, b
+	}
+	return status, b
+}
+
+// dispatch records the request, checks the token and injected failures, and
+// calls h.
+func (s *Server) dispatch(r *http.Request, h handler) (int, interface{}) {
+	path := strings.TrimPrefix(r.URL.Path, BasePath)
+	s.requests = append(s.requests, r.Method+" "+path)
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" || (s.Token != "" && token != s.Token) {
+		return errorf(http.StatusUnauthorized, "Unauthorized")
+	}
+
+	for i, f := range s.failures {
+		if f.method == r.Method && f.path == path {
+			s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			return errorf(f.status, "%s", http.StatusText(f.status))
+		}
+	}
+	return h(r)
+}
+
+// errorf returns an error response in the API's {"message": ...} format.
+func errorf(status int, format string, args ...interface{}) (int, interface{}) {
+	return status, map[string]interface{}{"message": fmt.Sprintf(format, args...)}
+}
+
+// notFound returns a 404 response for a missing resource.
+func notFound(kind, id string) (int, interface{}) {
+	return errorf(http.StatusNotFound, "%s %s not found", kind, id)
+}
+
+// invalid returns a 400 validation failure listing the offending fields.
+func invalid(fields ...string) (int, interface{}) {
+	errs := make([]map[string]string, 0, len(fields))
+	for _, f := range fields {
+		errs = append(errs, map[string]string{"field": f, "message": f + " is required"})
+	}
+	return http.StatusBadRequest, map[string]interface{}{
+		"message": "Validation failed",
+		"code":    "VALIDATION_ERROR",
+		"errors":  errs,
+	}
+}
+
+// decode reads the JSON request body into a document.
+func decode(r *http.Request) (doc, error) {
+	d := doc{}
+	if r.Body == nil || r.ContentLength == 0 {
+		return d, nil
+	}
+	if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// required returns the names of the fields missing or empty in d.
+func required(d doc, fields ...string) []string {
+	var missing []string
+	for _, f := range fields {
+		switch v := d[f].(type) {
+		case nil:
+			missing = append(missing, f)
+		case string:
+			if v == "" {
+				missing = append(missing, f)
+			}
+		case []interface{}:
+			if len(v) == 0 {
+				missing = append(missing, f)
+			}
+		}
+	}
+	return missing
+}
+
+// page returns one page of docs in the {"meta":...,"data":[...]} envelope,
+// honoring the offset and limit query parameters.
+func page(r *http.Request, docs []doc) (int, interface{}) {
+	q := r.URL.Query()
+	offset, _ := strconv.Atoi(q.Get("offset"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	data := []doc{}
+	if offset < len(docs) {
+		data = docs[offset:min(offset+limit, len(docs))]
+	}
+	return http.StatusOK, map[string]interface{}{
+		"meta": api.MetaInfo{Limit: limit, Offset: offset, Count: len(docs)},
+		"data": data,
+	}
+}
+
+// newID returns a new identifier in the API's 24 hex digit format.
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("%024x", s.nextID)
+}
+
+// insert stores d in c, filling in the identifier and timestamps when unset.
+func (s *Server) insert(c *collection, d doc) doc {
+	if str(d, "_id") == "" {
+		d["_id"] = s.newID()
+	}
+	ts := now()
+	if str(d, "createdAt") == "" {
+		d["createdAt"] = ts
+	}
+	if str(d, "updatedAt") == "" {
+		d["updatedAt"] = ts
+	}
+	return c.insert(d)
+}
@@ -0,0 +1,235 @@
+package cacheflytest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+)
+
+func TestServer_Services(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	svc, err := client.Services.Create(ctx, api.CreateServiceRequest{Name: "web", UniqueName: "web-1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if svc.ID == "" || svc.Status != "ACTIVE" {
+		t.Errorf("Expected an active service with an ID, got %+v", svc)
+	}
+
+	if _, err := client.Services.Create(ctx, api.CreateServiceRequest{Name: "web", UniqueName: "web-1"}); !cachefly.IsConflict(err) {
+		t.Errorf("Expected conflict for a duplicate uniqueName, got %v", err)
+	}
+	if _, err := client.Services.Create(ctx, api.CreateServiceRequest{Name: "web"}); !cachefly.IsValidation(err) {
+		t.Errorf("Expected validation error without uniqueName, got %v", err)
+	}
+	if _, err := client.Services.Get(ctx, "missing", "", false); !cachefly.IsNotFound(err) {
+		t.Errorf("Expected not found, got %v", err)
+	}
+
+	deactivated, err := client.Services.DeactivateServiceByID(ctx, svc.ID)
+	if err != nil || deactivated.Status != "DEACTIVATED" {
+		t.Errorf("Expected deactivated service, got %+v (%v)", deactivated, err)
+	}
+}
+
+func TestServer_Pagination(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	for i := 0; i < 25; i++ {
+		srv.AddService(api.Service{Name: fmt.Sprintf("svc-%d", i), UniqueName: fmt.Sprintf("svc-%d", i)})
+	}
+	client := srv.Client()
+
+	page, err := client.Services.List(context.Background(), api.ListOptions{Offset: 20, Limit: 10})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(page.Services) != 5 || page.Meta.Count != 25 || page.Meta.Offset != 20 {
+		t.Errorf("Unexpected page: %d services, meta %+v", len(page.Services), page.Meta)
+	}
+
+	all, err := api.ListAll(client.Services.All(context.Background(), api.ListOptions{Limit: 7}))
+	if err != nil || len(all) != 25 || all[24].Name != "svc-24" {
+		t.Errorf("Expected 25 services in order, got %d (%v)", len(all), err)
+	}
+}
+
+func TestServer_Auth(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	_, err := srv.Client(cachefly.WithToken("wrong")).Users.GetCurrentUser(context.Background())
+	if !cachefly.IsUnauthorized(err) {
+		t.Errorf("Expected unauthorized, got %v", err)
+	}
+	me, err := srv.Client().Users.GetCurrentUser(context.Background())
+	if err != nil || me.Username != "test" {
+		t.Errorf("Expected current user, got %+v (%v)", me, err)
+	}
+}
+
+func TestServer_FailNext(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	svc := srv.AddService(api.Service{Name: "web", UniqueName: "web"})
+	srv.FailNext(http.MethodGet, "/services/"+svc.ID, http.StatusServiceUnavailable)
+	client := srv.Client()
+
+	if _, err := client.Services.Get(context.Background(), svc.ID, "", false); !cachefly.IsServerError(err) {
+		t.Errorf("Expected injected server error, got %v", err)
+	}
+	if _, err := client.Services.Get(context.Background(), svc.ID, "", false); err != nil {
+		t.Errorf("Expected the failure to be consumed, got %v", err)
+	}
+
+	want := []string{"GET /services/" + svc.ID, "GET /services/" + svc.ID}
+	if got := srv.Requests(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Expected requests %v, got %v", want, got)
+	}
+}
+
+func TestServer_Domains(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	svc := srv.AddService(api.Service{Name: "web", UniqueName: "web"})
+	client := srv.Client()
+	ctx := context.Background()
+
+	d, err := client.ServiceDomains.Create(ctx, svc.ID, api.CreateServiceDomainRequest{Name: "cdn.example.com"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if d.Service != svc.ID || d.ValidationStatus != "PENDING" {
+		t.Errorf("Unexpected domain %+v", d)
+	}
+	if _, err := client.ServiceDomains.Create(ctx, svc.ID, api.CreateServiceDomainRequest{Name: "CDN.example.com"}); !cachefly.IsConflict(err) {
+		t.Errorf("Expected conflict for a duplicate domain, got %v", err)
+	}
+	if _, err := client.ServiceDomains.Create(ctx, "missing", api.CreateServiceDomainRequest{Name: "x.example.com"}); !cachefly.IsNotFound(err) {
+		t.Errorf("Expected not found for a missing service, got %v", err)
+	}
+	if err := client.ServiceDomains.DeleteByID(ctx, svc.ID, d.ID); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if _, err := client.ServiceDomains.GetByID(ctx, svc.ID, d.ID, ""); !cachefly.IsNotFound(err) {
+		t.Errorf("Expected deleted domain to be gone, got %v", err)
+	}
+}
+
+func TestServer_Options(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	svc := srv.AddService(api.Service{Name: "web", UniqueName: "web"})
+	client := srv.Client()
+	ctx := context.Background()
+
+	updated, err := client.ServiceOptions.UpdateOptions(ctx, svc.ID, api.ServiceOptions{"cors": true, "error_ttl": 60, "protectServeKeyEnabled": true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated["cors"] != true || updated["protectServeKeyEnabled"] != true {
+		t.Errorf("Unexpected options %v", updated)
+	}
+	if opts := srv.Options(svc.ID); opts["error_ttl"] != float64(60) {
+		t.Errorf("Expected stored error_ttl, got %v", opts)
+	} else if _, ok := opts["protectServeKeyEnabled"]; ok {
+		t.Errorf("Expected protectServeKeyEnabled to stay out of the options, got %v", opts)
+	}
+
+	key, err := client.ServiceOptions.GetProtectServeKey(ctx, svc.ID, false)
+	if err != nil || len(key.ProtectServeKey) != 32 {
+		t.Errorf("Expected a generated ProtectServe key, got %+v (%v)", key, err)
+	}
+
+	err = client.Services.Client.Put(ctx, "/services/"+svc.ID+"/options", map[string]interface{}{"edgetoorigin": true}, nil)
+	if !cachefly.IsValidation(err) {
+		t.Errorf("Expected read-only option to be rejected, got %v", err)
+	}
+}
+
+func TestServer_PurgeAndLogging(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	svc := srv.AddService(api.Service{Name: "web", UniqueName: "web"})
+	target := srv.AddLogTarget(api.LogTarget{Type: "S3_BUCKET"})
+	client := srv.Client()
+	ctx := context.Background()
+
+	if err := client.Services.Purge(ctx, svc.ID, api.PurgeRequest{Paths: []string{"/a.css"}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if purges := srv.Purges(svc.ID); len(purges) != 1 || purges[0].Paths[0] != "/a.css" {
+		t.Errorf("Expected the purge to be recorded, got %+v", purges)
+	}
+
+	if _, err := client.Services.EnableAccessLogging(ctx, svc.ID, api.EnableAccessLogsRequest{LogTarget: target.ID}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	got, err := client.LogTargets.GetByID(ctx, target.ID)
+	if err != nil || got.AccessLogsServices == nil || len(*got.AccessLogsServices) != 1 {
+		t.Errorf("Expected the service on the log target, got %+v (%v)", got, err)
+	}
+}
+
+func TestServer_Certificates(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	certPEM, keyPEM := selfSigned(t, "cdn.example.com", time.Now().Add(10*24*time.Hour))
+	cert, err := client.Certificates.Create(context.Background(), api.CreateCertificateRequest{Certificate: certPEM, CertificateKey: keyPEM})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cert.SubjectCommonName != "cdn.example.com" || !cert.Expiring || cert.Expired {
+		t.Errorf("Unexpected certificate %+v", cert)
+	}
+
+	_, err = client.Certificates.Create(context.Background(), api.CreateCertificateRequest{Certificate: "junk", CertificateKey: keyPEM})
+	var apiErr *cachefly.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid certificate, got %v", err)
+	}
+}
+
+func selfSigned(t *testing.T, cn string, notAfter time.Time) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     []string{cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
@@ -0,0 +1,144 @@
+package cacheflytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// doc is a stored resource in its JSON form.
+type doc = map[string]interface{}
+
+// collection is an ordered set of documents keyed by "_id".
+type collection struct {
+	docs  map[string]doc
+	order []string
+}
+
+func newCollection() *collection {
+	return &collection{docs: map[string]doc{}}
+}
+
+func (c *collection) insert(d doc) doc {
+	id, _ := d["_id"].(string)
+	if _, exists := c.docs[id]; !exists {
+		c.order = append(c.order, id)
+	}
+	c.docs[id] = d
+	return d
+}
+
+func (c *collection) get(id string) (doc, bool) {
+	d, ok := c.docs[id]
+	return d, ok
+}
+
+func (c *collection) delete(id string) bool {
+	if _, ok := c.docs[id]; !ok {
+		return false
+	}
+	delete(c.docs, id)
+	for i, v := range c.order {
+		if v == id {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// list returns the documents in insertion order, keeping those accepted by
+// every filter.
+func (c *collection) list(filters ...func(doc) bool) []doc {
+	var out []doc
+next:
+	for _, id := range c.order {
+		d := c.docs[id]
+		for _, f := range filters {
+			if !f(d) {
+				continue next
+			}
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// merge copies the fields of patch into d, skipping read-only fields.
+func merge(d, patch doc) {
+	for k, v := range patch {
+		switch k {
+		case "_id", "createdAt", "updatedAt":
+			continue
+		}
+		d[k] = v
+	}
+	d["updatedAt"] = now()
+}
+
+// toDoc converts a typed value into its JSON document form.
+func toDoc(v interface{}) doc {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("cacheflytest: cannot encode %T: %v", v, err))
+	}
+	var d doc
+	if err := json.Unmarshal(b, &d); err != nil {
+		panic(fmt.Sprintf("cacheflytest: cannot decode %T: %v", v, err))
+	}
+	return d
+}
+
+// fromDoc converts a document back into a typed value.
+func fromDoc[T any](d doc) T {
+	var out T
+	b, _ := json.Marshal(d)
+	json.Unmarshal(b, &out)
+	return out
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+func str(d doc, key string) string {
+	s, _ := d[key].(string)
+	return s
+}
+
+func strs(d doc, key string) []string {
+	var out []string
+	switch v := d[key].(type) {
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+	case []string:
+		out = append(out, v...)
+	}
+	return out
+}
+
+// fieldEquals filters documents whose field equals value; an empty value
+// matches everything.
+func fieldEquals(key, value string) func(doc) bool {
+	return func(d doc) bool { return value == "" || str(d, key) == value }
+}
+
+// fieldContains filters documents whose field contains value, ignoring case.
+func fieldContains(value string, keys ...string) func(doc) bool {
+	return func(d doc) bool {
+		if value == "" {
+			return true
+		}
+		for _, k := range keys {
+			if strings.Contains(strings.ToLower(str(d, k)), strings.ToLower(value)) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,129 @@
+package cachefly
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+)
+
+const (
+	// childTokenRefreshMargin is how long before expiry a child account
+	// token is replaced.
+	childTokenRefreshMargin = time.Minute
+
+	// childTokenMinReuse is how long a child account token is used at least,
+	// so that tokens expiring within childTokenRefreshMargin are not fetched
+	// again for every request.
+	childTokenMinReuse = 10 * time.Second
+
+	// childTokenFallbackTTL is how long a child account token is used when
+	// the API does not report when it expires.
+	childTokenFallbackTTL = 5 * time.Minute
+)
+
+// ForChildAccount returns a client authenticated as a child account of the
+// account c is authenticated as. The child token is obtained with
+// AccountsService.GetChildAccountAuthToken through c, cached, and refreshed
+// shortly before it expires, so the returned client can be used for as long
+// as c has access to the child.
+//
+// The child client has the same configuration as c, with its own rate limiter
+// and cache. Clients are reused: calling ForChildAccount again with the same
+// ID returns the same client.
+//
+// Example:
+//
+//	child, err := client.ForChildAccount(ctx, "child-account-id")
+//	services, err := child.Services.List(ctx, api.ListOptions{})
+func (c *Client) ForChildAccount(ctx context.Context, accountID string) (*Client, error) {
+	if accountID == "" {
+		return nil, fmt.Errorf("account ID is required")
+	}
+	c.childMu.Lock()
+	child, ok := c.children[accountID]
+	c.childMu.Unlock()
+	if ok {
+		return child, nil
+	}
+
+	tokens := &childToken{accounts: c.Accounts, id: accountID, now: time.Now}
+	// fail early when the child is not accessible
+	if _, err := tokens.Token(ctx); err != nil {
+		return nil, err
+	}
+	cfg := c.config
+	cfg.Token = ""
+	cfg.TokenProvider = tokens
+	child = newClient(cfg)
+
+	c.childMu.Lock()
+	defer c.childMu.Unlock()
+	if existing, ok := c.children[accountID]; ok {
+		return existing, nil
+	}
+	if c.children == nil {
+		c.children = map[string]*Client{}
+	}
+	c.children[accountID] = child
+	return child, nil
+}
+
+// ForEachChildAccount calls fn with a client for every child account, in the
+// order they are listed, and stops at the first error.
+func (c *Client) ForEachChildAccount(ctx context.Context, fn func(ctx context.Context, account api.Account, child *Client) error) error {
+	for account, err := range c.Accounts.All(ctx, api.ListAccountsOptions{IsChild: true}) {
+		if err != nil {
+			return err
+		}
+		child, err := c.ForChildAccount(ctx, account.ID)
+		if err != nil {
+			return fmt.Errorf("account %s: %w", account.ID, err)
+		}
+		if err := fn(ctx, account, child); err != nil {
+			return fmt.Errorf("account %s: %w", account.ID, err)
+		}
+	}
+	return nil
+}
+
+// childToken is a TokenProvider for a child account.
+type childToken struct {
+	accounts *api.AccountsService
+	id       string
+	now      func() time.Time
+
+	mu      sync.Mutex
+	token   string
+	refresh time.Time
+}
+
+func (t *childToken) Token(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != "" && t.now().Before(t.refresh) {
+		return t.token, nil
+	}
+	res, err := t.accounts.GetChildAccountAuthToken(ctx, t.id)
+	if err != nil {
+		return "", fmt.Errorf("failed to get token for child account %s: %w", t.id, err)
+	}
+	if res.Token == "" {
+		return "", fmt.Errorf("no token returned for child account %s", t.id)
+	}
+
+	t.token = res.Token
+	now := t.now()
+	if expiry := res.Expiry(); !expiry.IsZero() {
+		t.refresh = expiry.Add(-childTokenRefreshMargin)
+		if earliest := now.Add(childTokenMinReuse); t.refresh.Before(earliest) {
+			t.refresh = earliest
+		}
+	} else {
+		t.refresh = now.Add(childTokenFallbackTTL)
+	}
+	return t.token, nil
+}
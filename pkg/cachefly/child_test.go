@@ -0,0 +1,119 @@
+package cachefly
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+)
+
+// childServer issues numbered tokens for child accounts and records the
+// Authorization header of every other request.
+func childServer(t *testing.T, expiresAt time.Time) (*httptest.Server, *[]string, *int) {
+	var auth []string
+	issued := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/auth"):
+			if r.Header.Get("Authorization") != "Bearer parent" {
+				t.Errorf("Expected the parent token for %s, got %s", r.URL.Path, r.Header.Get("Authorization"))
+			}
+			issued++
+			id := strings.Split(r.URL.Path, "/")[4]
+			fmt.Fprintf(w, `{"token":"%s-%d","expiresAt":%q}`, id, issued, expiresAt.Format(time.RFC3339))
+		case r.URL.Path == "/api/2.6/accounts":
+			if r.URL.Query().Get("isChild") != "true" {
+				t.Errorf("Expected child accounts to be listed, got %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"meta":{"count":2},"data":[{"_id":"c1","isChild":true},{"_id":"c2","isChild":true}]}`))
+		default:
+			auth = append(auth, r.Header.Get("Authorization"))
+			w.Write([]byte(`{"_id":"me"}`))
+		}
+	}))
+	return server, &auth, &issued
+}
+
+func TestClient_ForChildAccount(t *testing.T) {
+	expiry := time.Now().Add(time.Hour)
+	server, auth, issued := childServer(t, expiry)
+	defer server.Close()
+
+	client := NewClient(WithToken("parent"), WithBaseURL(server.URL+"/api/2.6"))
+	ctx := context.Background()
+	child, err := client.ForChildAccount(ctx, "c1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if again, _ := client.ForChildAccount(ctx, "c1"); again != child {
+		t.Error("Expected the child client to be reused")
+	}
+
+	child.Accounts.Get(ctx, "")
+	child.Accounts.Get(ctx, "")
+	tokens := child.config.TokenProvider.(*childToken)
+	tokens.now = func() time.Time { return expiry.Add(-30 * time.Second) }
+	child.Accounts.Get(ctx, "")
+	client.Accounts.Get(ctx, "")
+
+	want := "Bearer c1-1,Bearer c1-1,Bearer c1-2,Bearer parent"
+	if got := strings.Join(*auth, ","); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if *issued != 2 {
+		t.Errorf("Expected the token to be refreshed once before expiry, got %d tokens", *issued)
+	}
+}
+
+func TestClient_ForChildAccount_ShortLivedToken(t *testing.T) {
+	server, auth, issued := childServer(t, time.Now().Add(20*time.Second))
+	defer server.Close()
+
+	client := NewClient(WithToken("parent"), WithBaseURL(server.URL+"/api/2.6"))
+	ctx := context.Background()
+	child, err := client.ForChildAccount(ctx, "c1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	child.Accounts.Get(ctx, "")
+	child.Accounts.Get(ctx, "")
+
+	if got := strings.Join(*auth, ","); got != "Bearer c1-1,Bearer c1-1" {
+		t.Errorf("Expected the first token to be reused, got %s", got)
+	}
+	if *issued != 1 {
+		t.Errorf("Expected a single token for a token expiring within the refresh margin, got %d tokens", *issued)
+	}
+}
+
+func TestClient_ForEachChildAccount(t *testing.T) {
+	server, auth, _ := childServer(t, time.Now().Add(time.Hour))
+	defer server.Close()
+
+	client := NewClient(WithToken("parent"), WithBaseURL(server.URL+"/api/2.6"))
+	var ids []string
+	err := client.ForEachChildAccount(context.Background(), func(ctx context.Context, account api.Account, child *Client) error {
+		ids = append(ids, account.ID)
+		_, err := child.Accounts.Get(ctx, "")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Join(ids, ",") != "c1,c2" || strings.Join(*auth, ",") != "Bearer c1-1,Bearer c2-2" {
+		t.Errorf("Expected each child to be called with its own token, got %v and %v", ids, *auth)
+	}
+
+	err = client.ForEachChildAccount(context.Background(), func(ctx context.Context, account api.Account, child *Client) error {
+		return fmt.Errorf("boom")
+	})
+	if err == nil || !strings.Contains(err.Error(), "account c1: boom") {
+		t.Errorf("Expected the first failure, got %v", err)
+	}
+}
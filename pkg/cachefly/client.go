@@ -25,7 +25,12 @@
 package cachefly
 
 import (
+	"context"
+	"log/slog"
+	"net/http"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/cachefly/cachefly-sdk-go/internal/httpclient"
 	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
@@ -38,6 +43,11 @@ import (
 // specific aspects of the CacheFly platform.
 type Client struct {
 	httpClient *httpclient.Client
+	cache      *httpclient.Cache
+	config     ClientConfig
+
+	childMu  sync.Mutex
+	children map[string]*Client
 
 	// API service groups
 
@@ -110,8 +120,41 @@ type ClientConfig struct {
 	// Token is the Bearer token for API authentication
 	Token string
 
+	// TokenProvider supplies the token for each request, overriding Token
+	TokenProvider TokenProvider
+
 	// BaseURL overrides the default API base URL
 	BaseURL string
+
+	// RetryPolicy enables automatic retries of failed requests when set
+	RetryPolicy *RetryPolicy
+
+	// RateLimit is the maximum sustained number of requests per second; 0 disables limiting
+	RateLimit float64
+
+	// RateLimitBurst is the number of requests that may be sent at once before RateLimit applies
+	RateLimitBurst int
+
+	// HTTPClient is the underlying HTTP client; it is copied, not modified
+	HTTPClient *http.Client
+
+	// Timeout overrides the request timeout of HTTPClient
+	Timeout time.Duration
+
+	// UserAgent overrides the default User-Agent header
+	UserAgent string
+
+	// Middleware wraps every API request, the first entry being the outermost
+	Middleware []Middleware
+
+	// Cache enables caching of rarely-changing lookups when set
+	Cache *CachePolicy
+
+	// Instrumenter observes every API call when set
+	Instrumenter Instrumenter
+
+	// Logger logs every API request when set, with secrets redacted
+	Logger *slog.Logger
 }
 
 // WithToken sets the Bearer token for API authentication.
@@ -128,6 +171,46 @@ func WithToken(token string) Option {
 	}
 }
 
+// WithTokenProvider sets a provider that supplies the API token for every
+// request, overriding WithToken. Use it to rotate tokens in long-running
+// programs without creating a new client.
+//
+// Example:
+//
+//	client := cachefly.NewClient(
+//		cachefly.WithTokenProvider(cachefly.FileToken("/run/secrets/cachefly-token")),
+//	)
+func WithTokenProvider(p TokenProvider) Option {
+	return func(c *ClientConfig) {
+		c.TokenProvider = p
+	}
+}
+
+// WithProfile authenticates with a named profile of the config file at
+// DefaultConfigPath, the file managed by "cachefly config set". The token is
+// read with ProfileToken, so updates to the file are picked up; the base URL
+// of the profile, if any, is applied when the client is created. An empty
+// name selects the profile as described in ConfigFile.Profile.
+//
+// Example:
+//
+//	client := cachefly.NewClient(cachefly.WithProfile("staging"))
+func WithProfile(name string) Option {
+	return func(c *ClientConfig) {
+		path, err := DefaultConfigPath()
+		if err != nil {
+			c.TokenProvider = TokenProviderFunc(func(context.Context) (string, error) { return "", err })
+			return
+		}
+		c.TokenProvider = ProfileToken(path, name)
+		if cfg, err := LoadConfigFile(path); err == nil {
+			if p, _, ok := cfg.Profile(name); ok && p.BaseURL != "" {
+				c.BaseURL = p.BaseURL
+			}
+		}
+	}
+}
+
 // WithBaseURL overrides the default API base URL.
 //
 // This is useful for testing against different environments
@@ -145,12 +228,158 @@ func WithBaseURL(url string) Option {
 	}
 }
 
+// WithRetryPolicy enables automatic retries of failed API requests.
+//
+// Transient failures (network errors, 429, 502, 503 and 504 responses by
+// default) are retried with exponential backoff, honoring the Retry-After
+// header. Only idempotent methods are retried unless the policy sets
+// RetryNonIdempotent.
+//
+// Example:
+//
+//	policy := cachefly.DefaultRetryPolicy()
+//	policy.OnRetry = func(e cachefly.RetryEvent) {
+//		log.Printf("retrying %s %s after %v: %v", e.Method, e.URL, e.Delay, e.Err)
+//	}
+//	client := cachefly.NewClient(
+//		cachefly.WithToken("token"),
+//		cachefly.WithRetryPolicy(policy),
+//	)
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *ClientConfig) {
+		c.RetryPolicy = &policy
+	}
+}
+
+// WithRateLimit throttles outgoing API requests with a client-side token bucket.
+//
+// The limiter allows rps requests per second on average with bursts of up to
+// burst requests, and is shared by every service group of the client. Calls
+// block until a token is available or their context is done. When the API
+// reports through rate limit headers that the quota is exhausted, or responds
+// with 429 and Retry-After, all requests pause until the quota resets.
+//
+// Example:
+//
+//	client := cachefly.NewClient(
+//		cachefly.WithToken("token"),
+//		cachefly.WithRateLimit(10, 20),
+//	)
+func WithRateLimit(rps float64, burst int) Option {
+	return func(c *ClientConfig) {
+		c.RateLimit = rps
+		c.RateLimitBurst = burst
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send API requests.
+//
+// Use it to configure proxies, mTLS or a custom transport. The client is
+// copied, so middleware added with WithMiddleware does not modify it.
+//
+// Example:
+//
+//	hc := &http.Client{Transport: &http.Transport{Proxy: http.ProxyFromEnvironment}}
+//	client := cachefly.NewClient(
+//		cachefly.WithToken("token"),
+//		cachefly.WithHTTPClient(hc),
+//	)
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *ClientConfig) {
+		c.HTTPClient = hc
+	}
+}
+
+// WithTimeout sets the timeout of each HTTP request, overriding the default
+// of 35 seconds and the timeout of a client set with WithHTTPClient.
+func WithTimeout(d time.Duration) Option {
+	return func(c *ClientConfig) {
+		c.Timeout = d
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) Option {
+	return func(c *ClientConfig) {
+		c.UserAgent = ua
+	}
+}
+
+// WithMiddleware wraps every API request made by the service groups.
+//
+// Middleware is applied in the order given, the first being the outermost,
+// and may be passed in several calls.
+//
+// Example:
+//
+//	logging := func(next http.RoundTripper) http.RoundTripper {
+//		return cachefly.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+//			start := time.Now()
+//			resp, err := next.RoundTrip(req)
+//			log.Printf("%s %s took %v", req.Method, req.URL.Path, time.Since(start))
+//			return resp, err
+//		})
+//	}
+//	client := cachefly.NewClient(
+//		cachefly.WithToken("token"),
+//		cachefly.WithMiddleware(logging),
+//	)
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *ClientConfig) {
+		c.Middleware = append(c.Middleware, mw...)
+	}
+}
+
+// WithLogger logs every API request to logger: the method, path, status and
+// duration at info level, transport failures at warn level and, when the
+// logger is enabled for debug, the request and response headers and bodies.
+//
+// Secrets are redacted before they reach the logger: the Authorization
+// header, and in JSON bodies the values of fields such as secretKey,
+// accessKey, password, apiKey, jsonKey, ftpPassword, protectServeKey, token
+// and certificateKey. Bodies that are not JSON are logged by size only.
+//
+// Example:
+//
+//	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+//	client := cachefly.NewClient(
+//		cachefly.WithToken("token"),
+//		cachefly.WithLogger(logger),
+//	)
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *ClientConfig) {
+		c.Logger = logger
+	}
+}
+
+// WithCache caches the responses of rarely-changing lookups: options
+// metadata, TLS profiles, delivery regions, script definitions and the rules,
+// image optimization and script config schemas.
+//
+// Responses are cached per endpoint for the TTL the policy sets for their
+// resource; resources without a TTL are always fetched. Errors are never
+// cached. Use Client.InvalidateCache to drop entries early, e.g. after
+// changing a TLS profile.
+//
+// Example:
+//
+//	client := cachefly.NewClient(
+//		cachefly.WithToken("token"),
+//		cachefly.WithCache(cachefly.DefaultCachePolicy()),
+//	)
+func WithCache(policy CachePolicy) Option {
+	return func(c *ClientConfig) {
+		c.Cache = &policy
+	}
+}
+
 // NewClient initializes and returns a new CacheFly API client.
 //
 // The client is configured with functional options and provides
 // access to all CacheFly API service groups.
 //
-// Authentication is required - use WithToken() to provide your API token.
+// Authentication is required - use WithToken() to provide your API token, or
+// WithTokenProvider() or WithProfile() to resolve it per request.
 //
 // Example:
 //
@@ -180,14 +409,40 @@ func NewClient(opts ...Option) *Client {
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	return newClient(*cfg)
+}
+
+// newClient builds a client from a complete configuration.
+func newClient(cfg ClientConfig) *Client {
+	var limiter *httpclient.RateLimiter
+	if cfg.RateLimit > 0 {
+		limiter = httpclient.NewRateLimiter(cfg.RateLimit, cfg.RateLimitBurst)
+	}
+
+	var cache *httpclient.Cache
+	if cfg.Cache != nil {
+		cache = httpclient.NewCache(*cfg.Cache)
+	}
 
 	hc := httpclient.New(httpclient.Config{
-		BaseURL:   cfg.BaseURL,
-		AuthToken: cfg.Token,
+		BaseURL:       cfg.BaseURL,
+		AuthToken:     cfg.Token,
+		TokenProvider: cfg.TokenProvider,
+		RetryPolicy:   cfg.RetryPolicy,
+		RateLimiter:   limiter,
+		Cache:         cache,
+		HTTPClient:    cfg.HTTPClient,
+		Timeout:       cfg.Timeout,
+		UserAgent:     cfg.UserAgent,
+		Middleware:    cfg.Middleware,
+		Instrumenter:  cfg.Instrumenter,
+		Logger:        cfg.Logger,
 	})
 
 	return &Client{
 		httpClient:                 hc,
+		cache:                      cache,
+		config:                     cfg,
 		Services:                   &api.ServicesService{Client: hc},
 		Accounts:                   &api.AccountsService{Client: hc},
 		ServiceDomains:             &api.ServiceDomainsService{Client: hc},
@@ -53,6 +53,15 @@
 //	    log.Printf("Failed to get service: %v", err)
 //	}
 //
+// Non-2xx API responses are returned as *APIError, which carries the status
+// code, request ID, raw body and decoded error payload. Use errors.As to
+// inspect it, or the helpers IsNotFound, IsConflict, IsRateLimited and
+// IsUnauthorized to branch on common cases:
+//
+//	if cachefly.IsNotFound(err) {
+//	    // the service does not exist
+//	}
+//
 // # Configuration Options
 //
 // The client supports several configuration options:
@@ -60,6 +69,14 @@
 //	client := cachefly.NewClient(
 //	    cachefly.WithToken("your-token"),           // API authentication
 //	    cachefly.WithBaseURL("https://api.example"), // Custom API endpoint
+//	    cachefly.WithRetryPolicy(cachefly.DefaultRetryPolicy()), // Retry transient failures
+//	    cachefly.WithCache(cachefly.DefaultCachePolicy()),       // Cache metadata and schemas
+//	)
+//
+// Tokens can also be resolved per request, e.g. to pick up rotated secrets:
+//
+//	client := cachefly.NewClient(
+//	    cachefly.WithTokenProvider(cachefly.FileToken("/run/secrets/cachefly-token")),
 //	)
 //
 // # Examples
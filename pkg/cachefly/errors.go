@@ -0,0 +1,80 @@
+package cachefly
+
+import (
+	"errors"
+
+	"github.com/cachefly/cachefly-sdk-go/internal/httpclient"
+)
+
+// APIError is returned by every service group method when the CacheFly API
+// responds with a status code of 400 or above.
+//
+// It carries the status code, request method and URL, the request ID header,
+// the raw response body and the decoded error payload:
+//
+//	_, err := client.Services.GetByID(ctx, "srv_123")
+//	var apiErr *cachefly.APIError
+//	if errors.As(err, &apiErr) {
+//		log.Printf("status=%d request=%s: %s", apiErr.StatusCode, apiErr.RequestID, apiErr.Message)
+//	}
+type APIError = httpclient.APIError
+
+// FieldError describes a problem with a single request field reported by the API.
+type FieldError = httpclient.FieldError
+
+// Sentinel errors for use with errors.Is. An *APIError matches the sentinel
+// corresponding to its status code.
+var (
+	ErrBadRequest   = httpclient.ErrBadRequest   // 400
+	ErrUnauthorized = httpclient.ErrUnauthorized // 401
+	ErrForbidden    = httpclient.ErrForbidden    // 403
+	ErrNotFound     = httpclient.ErrNotFound     // 404
+	ErrConflict     = httpclient.ErrConflict     // 409
+	ErrValidation   = httpclient.ErrValidation   // 422
+	ErrRateLimited  = httpclient.ErrRateLimited  // 429
+	ErrServer       = httpclient.ErrServer       // 5xx
+)
+
+// IsNotFound reports whether err is an API error with status 404.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict reports whether err is an API error with status 409.
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsRateLimited reports whether err is an API error with status 429.
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsUnauthorized reports whether err is an API error with status 401.
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsForbidden reports whether err is an API error with status 403.
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsValidation reports whether err is an API error with status 400 or 422.
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrBadRequest) || errors.Is(err, ErrValidation)
+}
+
+// IsServerError reports whether err is an API error with a 5xx status.
+func IsServerError(err error) bool {
+	return errors.Is(err, ErrServer)
+}
+
+// StatusCode returns the HTTP status code of an API error, or 0 if err is not an *APIError.
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
@@ -0,0 +1,221 @@
+package cachefly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+)
+
+// DefaultWalkConcurrency is the number of accounts WalkAccounts processes at
+// once when WalkOptions.Concurrency is not set.
+const DefaultWalkConcurrency = 4
+
+// ErrAccountCycle is wrapped by AccountNode.Err for child accounts that are
+// each other's ancestors, or descend from such accounts, and so cannot be
+// placed in the hierarchy.
+var ErrAccountCycle = errors.New("account parents form a cycle")
+
+// AccountNode is an account in the account hierarchy.
+type AccountNode struct {
+	Account  api.Account
+	Parent   *AccountNode
+	Children []*AccountNode
+	// Err is set for accounts attached to the root because they cannot be
+	// reached through their parents; it wraps ErrAccountCycle.
+	Err error
+}
+
+// Depth returns the number of ancestors of the node in the tree.
+func (n *AccountNode) Depth() int {
+	depth := 0
+	for p := n.Parent; p != nil; p = p.Parent {
+		depth++
+	}
+	return depth
+}
+
+// AccountTree returns the hierarchy of the account c is authenticated as: the
+// current account with its child accounts, nested by Account.Parent. Child
+// accounts whose parent is not visible are attached to the current account.
+// Accounts whose parents form a cycle are attached to the current account too,
+// with an Err wrapping ErrAccountCycle.
+func (c *Client) AccountTree(ctx context.Context) (*AccountNode, error) {
+	me, err := c.Accounts.Get(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	root := &AccountNode{Account: *me}
+
+	var nodes []*AccountNode
+	byID := map[string]*AccountNode{me.ID: root}
+	for account, err := range c.Accounts.All(ctx, api.ListAccountsOptions{IsChild: true}) {
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := byID[account.ID]; ok {
+			continue
+		}
+		node := &AccountNode{Account: account}
+		byID[account.ID] = node
+		nodes = append(nodes, node)
+	}
+	for _, node := range nodes {
+		parent := root
+		if node.Account.Parent != nil {
+			if p, ok := byID[*node.Account.Parent]; ok && p != node {
+				parent = p
+			}
+		}
+		node.Parent = parent
+		parent.Children = append(parent.Children, node)
+	}
+
+	reachable := map[*AccountNode]bool{}
+	root.Walk(func(n *AccountNode) { reachable[n] = true })
+	var cycle []*AccountNode
+	var ids []string
+	for _, node := range nodes {
+		if !reachable[node] {
+			cycle = append(cycle, node)
+			ids = append(ids, node.Account.ID)
+		}
+	}
+	if len(cycle) > 0 {
+		slices.Sort(ids)
+		err := fmt.Errorf("%w: %s", ErrAccountCycle, strings.Join(ids, ", "))
+		for _, node := range cycle {
+			node.Parent, node.Children, node.Err = root, nil, err
+			root.Children = append(root.Children, node)
+		}
+	}
+	return root, nil
+}
+
+// Walk calls fn for n and its descendants in depth-first order.
+func (n *AccountNode) Walk(fn func(*AccountNode)) {
+	fn(n)
+	for _, child := range n.Children {
+		child.Walk(fn)
+	}
+}
+
+// WalkOptions configures WalkAccounts.
+type WalkOptions struct {
+	// Concurrency is the maximum number of accounts processed at once.
+	Concurrency int
+	// IncludeParent also runs the function for the account the client is
+	// authenticated as.
+	IncludeParent bool
+}
+
+// AccountResult is the outcome of running a function for one account.
+type AccountResult[T any] struct {
+	Account api.Account
+	// Depth is the depth of the account in the hierarchy, 1 for direct
+	// children of the parent.
+	Depth int
+	Value T
+	Err   error
+}
+
+// WalkAccounts runs fn for every child account of the account c is
+// authenticated as, with a client scoped to that account, at most
+// opts.Concurrency at a time. Each client is obtained with ForChildAccount
+// from the client of the account's parent, so grandchildren are reached
+// through their parents.
+//
+// A failure for one account, including a panic in fn, does not stop the
+// others: it is recorded in the Err of that account's result. Results are in
+// depth-first order of the hierarchy. fn is not run for accounts whose
+// parents form a cycle; their Err wraps ErrAccountCycle. The returned error is
+// only set when the hierarchy cannot be listed. Accounts not started before ctx is done get
+// ctx.Err().
+//
+// Example:
+//
+//	results, err := cachefly.WalkAccounts(ctx, client, cachefly.WalkOptions{Concurrency: 8},
+//		func(ctx context.Context, account api.Account, c *cachefly.Client) (int, error) {
+//			resp, err := c.Services.List(ctx, api.ListOptions{Limit: 1})
+//			if err != nil {
+//				return 0, err
+//			}
+//			return resp.Meta.Count, nil
+//		})
+func WalkAccounts[T any](ctx context.Context, c *Client, opts WalkOptions, fn func(ctx context.Context, account api.Account, client *Client) (T, error)) ([]AccountResult[T], error) {
+	root, err := c.AccountTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var nodes []*AccountNode
+	root.Walk(func(n *AccountNode) {
+		if n != root || opts.IncludeParent {
+			nodes = append(nodes, n)
+		}
+	})
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultWalkConcurrency
+	}
+	results := make([]AccountResult[T], len(nodes))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		results[i] = AccountResult[T]{Account: node.Account, Depth: node.Depth()}
+		if node.Err != nil {
+			results[i].Err = node.Err
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(res *AccountResult[T], node *AccountNode) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			res.Value, res.Err = runForAccount(ctx, c, node, node == root, fn)
+		}(&results[i], node)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+func runForAccount[T any](ctx context.Context, c *Client, node *AccountNode, self bool, fn func(context.Context, api.Account, *Client) (T, error)) (value T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic for account %s: %v\n%s", node.Account.ID, r, debug.Stack())
+		}
+	}()
+	if err := ctx.Err(); err != nil {
+		return value, err
+	}
+	client := c
+	if !self {
+		if client, err = nodeClient(ctx, c, node); err != nil {
+			return value, err
+		}
+	}
+	return fn(ctx, node.Account, client)
+}
+
+// nodeClient returns a client for node, scoped through the clients of its
+// ancestors below the root, which c is authenticated as.
+func nodeClient(ctx context.Context, c *Client, node *AccountNode) (*Client, error) {
+	if node.Parent == nil {
+		return c, nil
+	}
+	parent, err := nodeClient(ctx, c, node.Parent)
+	if err != nil {
+		return nil, err
+	}
+	return parent.ForChildAccount(ctx, node.Account.ID)
+}
@@ -0,0 +1,212 @@
+package cachefly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	api "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/api/v2_6"
+)
+
+// hierarchyServer serves parent "p" with children c1 and c2, grandchild g1
+// under c1 and o1 whose parent is not visible. A child token is only issued
+// to the token of the child's parent, and never for c2.
+func hierarchyServer(t *testing.T) *httptest.Server {
+	parents := map[string]string{"c1": "parent", "c2": "parent", "o1": "parent", "g1": "token-c1"}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch path := strings.TrimPrefix(r.URL.Path, "/api/2.6"); {
+		case path == "/accounts/me":
+			w.Write([]byte(`{"_id":"p","isParent":true}`))
+		case path == "/accounts":
+			w.Write([]byte(`{"meta":{"count":4},"data":[
+				{"_id":"c1","parent":"p","isChild":true,"isParent":true},
+				{"_id":"g1","parent":"c1","isChild":true},
+				{"_id":"c2","parent":"p","isChild":true},
+				{"_id":"o1","parent":"elsewhere","isChild":true}
+			]}`))
+		case strings.HasSuffix(path, "/auth"):
+			id := strings.Split(path, "/")[2]
+			if id == "c2" || r.Header.Get("Authorization") != "Bearer "+parents[id] {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"message":"forbidden"}`))
+				return
+			}
+			fmt.Fprintf(w, `{"token":"token-%s","expiresAt":%q}`, id, time.Now().Add(time.Hour).Format(time.RFC3339))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+	}))
+}
+
+func TestClient_AccountTree(t *testing.T) {
+	server := hierarchyServer(t)
+	defer server.Close()
+
+	client := NewClient(WithToken("parent"), WithBaseURL(server.URL+"/api/2.6"))
+	root, err := client.AccountTree(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var got []string
+	root.Walk(func(n *AccountNode) {
+		got = append(got, fmt.Sprintf("%s:%d", n.Account.ID, n.Depth()))
+	})
+	if want := "p:0,c1:1,g1:2,c2:1,o1:1"; strings.Join(got, ",") != want {
+		t.Errorf("Expected %s, got %s", want, strings.Join(got, ","))
+	}
+}
+
+func TestWalkAccounts(t *testing.T) {
+	server := hierarchyServer(t)
+	defer server.Close()
+
+	client := NewClient(WithToken("parent"), WithBaseURL(server.URL+"/api/2.6"))
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	results, err := WalkAccounts(context.Background(), client, WalkOptions{Concurrency: 2, IncludeParent: true},
+		func(ctx context.Context, account api.Account, c *Client) (string, error) {
+			mu.Lock()
+			running++
+			maxRunning = max(maxRunning, running)
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				running--
+				mu.Unlock()
+			}()
+			time.Sleep(5 * time.Millisecond)
+
+			switch account.ID {
+			case "c1":
+				return "", errors.New("audit failed")
+			case "o1":
+				panic("unexpected account")
+			}
+			return account.ID, nil
+		})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if maxRunning > 2 {
+		t.Errorf("Expected at most 2 accounts at once, got %d", maxRunning)
+	}
+
+	got := map[string]string{}
+	for _, r := range results {
+		if r.Err != nil {
+			got[r.Account.ID] = "error: " + strings.SplitN(r.Err.Error(), "\n", 2)[0]
+		} else {
+			got[r.Account.ID] = r.Value
+		}
+	}
+	want := map[string]string{
+		"p":  "p",
+		"c1": "error: audit failed",
+		"g1": "g1",
+		"c2": "error: failed to get token for child account c2: ",
+		"o1": "error: panic for account o1: unexpected account",
+	}
+	for id, w := range want {
+		if !strings.HasPrefix(got[id], w) {
+			t.Errorf("Expected %s for %s, got %q", w, id, got[id])
+		}
+	}
+	if len(results) != 5 || results[0].Account.ID != "p" || results[2].Depth != 2 {
+		t.Errorf("Expected results in hierarchy order, got %+v", results)
+	}
+}
+
+// cycleServer serves parent "p" with child c1 and accounts a and b, each
+// the parent of the other.
+func cycleServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch path := strings.TrimPrefix(r.URL.Path, "/api/2.6"); path {
+		case "/accounts/me":
+			w.Write([]byte(`{"_id":"p"}`))
+		case "/accounts":
+			w.Write([]byte(`{"meta":{"count":3},"data":[
+				{"_id":"c1","parent":"p","isChild":true},
+				{"_id":"a","parent":"b","isChild":true},
+				{"_id":"b","parent":"a","isChild":true}
+			]}`))
+		case "/accounts/c1/auth":
+			fmt.Fprintf(w, `{"token":"token-c1","expiresAt":%q}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+	}))
+}
+
+func TestClient_AccountTree_Cycle(t *testing.T) {
+	server := cycleServer(t)
+	defer server.Close()
+
+	client := NewClient(WithToken("parent"), WithBaseURL(server.URL+"/api/2.6"))
+	root, err := client.AccountTree(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var got []string
+	root.Walk(func(n *AccountNode) {
+		got = append(got, fmt.Sprintf("%s:%d", n.Account.ID, n.Depth()))
+		if n.Account.ID == "a" || n.Account.ID == "b" {
+			if !errors.Is(n.Err, ErrAccountCycle) || !strings.HasSuffix(n.Err.Error(), ": a, b") {
+				t.Errorf("Expected a cycle error listing a and b for %s, got %v", n.Account.ID, n.Err)
+			}
+		} else if n.Err != nil {
+			t.Errorf("Expected no error for %s, got %v", n.Account.ID, n.Err)
+		}
+	})
+	if want := "p:0,c1:1,a:1,b:1"; strings.Join(got, ",") != want {
+		t.Errorf("Expected %s, got %s", want, strings.Join(got, ","))
+	}
+}
+
+func TestWalkAccounts_Cycle(t *testing.T) {
+	server := cycleServer(t)
+	defer server.Close()
+
+	client := NewClient(WithToken("parent"), WithBaseURL(server.URL+"/api/2.6"))
+	results, err := WalkAccounts(context.Background(), client, WalkOptions{},
+		func(ctx context.Context, account api.Account, c *Client) (string, error) {
+			return account.ID, nil
+		})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(results) != 3 || results[0].Value != "c1" || results[0].Err != nil {
+		t.Fatalf("Expected c1 to be walked, got %+v", results)
+	}
+	for _, r := range results[1:] {
+		if !errors.Is(r.Err, ErrAccountCycle) || r.Value != "" {
+			t.Errorf("Expected a cycle error for %s, got %+v", r.Account.ID, r)
+		}
+	}
+}
+
+func TestWalkAccounts_Canceled(t *testing.T) {
+	server := hierarchyServer(t)
+	defer server.Close()
+
+	client := NewClient(WithToken("parent"), WithBaseURL(server.URL+"/api/2.6"))
+	ctx, cancel := context.WithCancel(context.Background())
+	results, err := WalkAccounts(ctx, client, WalkOptions{Concurrency: 1},
+		func(ctx context.Context, account api.Account, c *Client) (bool, error) {
+			cancel()
+			return true, nil
+		})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !results[0].Value || !errors.Is(results[len(results)-1].Err, context.Canceled) {
+		t.Errorf("Expected the remaining accounts to be skipped, got %+v", results)
+	}
+}
@@ -0,0 +1,29 @@
+package cachefly
+
+import "github.com/cachefly/cachefly-sdk-go/internal/httpclient"
+
+// Instrumenter observes API calls, e.g. to trace them or record metrics. See
+// WithInstrumentation and the otelcachefly package.
+type Instrumenter = httpclient.Instrumenter
+
+// CallInfo describes an API call passed to an Instrumenter.
+type CallInfo = httpclient.CallInfo
+
+// CallResult is the outcome of an API call passed to an Instrumenter.
+type CallResult = httpclient.CallResult
+
+// WithInstrumentation reports every API call to i. A call covers all of its
+// attempts when retries are enabled; responses served from the cache (see
+// WithCache) are not reported.
+//
+// Example:
+//
+//	client := cachefly.NewClient(
+//		cachefly.WithToken("token"),
+//		cachefly.WithInstrumentation(otelcachefly.New()),
+//	)
+func WithInstrumentation(i Instrumenter) Option {
+	return func(c *ClientConfig) {
+		c.Instrumenter = i
+	}
+}
@@ -0,0 +1,155 @@
+// Package otelcachefly instruments CacheFly API calls with OpenTelemetry.
+//
+// Every API call becomes a client span named after the service group and
+// operation, e.g. "ServiceOptions.UpdateOptions", with the HTTP method, path,
+// response status code, number of retries and the IDs of the resources in the
+// path (such as cachefly.service.id) as attributes. Calls are also counted
+// and timed:
+//
+//	cachefly.client.requests  counter of calls
+//	cachefly.client.errors    counter of failed calls
+//	cachefly.client.duration  histogram of call durations in seconds
+//
+// Metrics carry the operation, method and status code as attributes.
+//
+// Example:
+//
+//	client := cachefly.NewClient(
+//		cachefly.WithToken("token"),
+//		cachefly.WithInstrumentation(otelcachefly.New()),
+//	)
+package otelcachefly
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+)
+
+// ScopeName is the instrumentation scope of the tracer and meter.
+const ScopeName = "github.com/cachefly/cachefly-sdk-go/pkg/cachefly/otelcachefly"
+
+// Attribute keys.
+const (
+	AttrOperation  = attribute.Key("cachefly.operation")
+	AttrMethod     = attribute.Key("http.request.method")
+	AttrPath       = attribute.Key("url.path")
+	AttrStatusCode = attribute.Key("http.response.status_code")
+	AttrRetries    = attribute.Key("http.request.resend_count")
+)
+
+// Option configures the instrumentation.
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// WithTracerProvider sets the tracer provider, defaulting to the global one.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the meter provider, defaulting to the global one.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// Instrumenter records CacheFly API calls as spans and metrics. It implements
+// cachefly.Instrumenter.
+type Instrumenter struct {
+	tracer   trace.Tracer
+	requests metric.Int64Counter
+	errors   metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+var _ cachefly.Instrumenter = (*Instrumenter)(nil)
+
+// New returns an Instrumenter. Errors creating the metric instruments are
+// reported to otel.Handle and leave the affected instrument as a no-op.
+func New(opts ...Option) *Instrumenter {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(ScopeName)
+	i := &Instrumenter{tracer: cfg.tracerProvider.Tracer(ScopeName)}
+	var err error
+	if i.requests, err = meter.Int64Counter("cachefly.client.requests",
+		metric.WithDescription("Number of CacheFly API calls"), metric.WithUnit("{call}")); err != nil {
+		otel.Handle(err)
+	}
+	if i.errors, err = meter.Int64Counter("cachefly.client.errors",
+		metric.WithDescription("Number of failed CacheFly API calls"), metric.WithUnit("{call}")); err != nil {
+		otel.Handle(err)
+	}
+	if i.duration, err = meter.Float64Histogram("cachefly.client.duration",
+		metric.WithDescription("Duration of CacheFly API calls, including retries"), metric.WithUnit("s")); err != nil {
+		otel.Handle(err)
+	}
+	return i
+}
+
+// StartCall starts a span for an API call.
+func (i *Instrumenter) StartCall(ctx context.Context, call cachefly.CallInfo) (context.Context, func(cachefly.CallResult)) {
+	name := call.Operation
+	if name == "" {
+		name = "CacheFly " + call.Method
+	}
+	attrs := []attribute.KeyValue{
+		AttrMethod.String(call.Method),
+		AttrPath.String(call.Path),
+	}
+	if call.Operation != "" {
+		attrs = append(attrs, AttrOperation.String(call.Operation))
+	}
+	for typ, id := range call.ResourceIDs {
+		attrs = append(attrs, attribute.String("cachefly."+typ+".id", id))
+	}
+	ctx, span := i.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+
+	return ctx, func(res cachefly.CallResult) {
+		if res.StatusCode != 0 {
+			span.SetAttributes(AttrStatusCode.Int(res.StatusCode))
+		}
+		if res.Retries > 0 {
+			span.SetAttributes(AttrRetries.Int(res.Retries))
+		}
+		if res.Err != nil {
+			span.RecordError(res.Err)
+			span.SetStatus(codes.Error, res.Err.Error())
+		}
+		span.End()
+
+		set := metric.WithAttributeSet(attribute.NewSet(
+			AttrOperation.String(name),
+			AttrMethod.String(call.Method),
+			AttrStatusCode.Int(res.StatusCode),
+		))
+		if i.requests != nil {
+			i.requests.Add(ctx, 1, set)
+		}
+		if res.Err != nil && i.errors != nil {
+			i.errors.Add(ctx, 1, set)
+		}
+		if i.duration != nil {
+			i.duration.Record(ctx, res.Duration.Seconds(), set)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package otelcachefly
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/cachefly/cachefly-sdk-go/pkg/cachefly"
+)
+
+func TestInstrumenter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "missing") {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"not found"}`))
+			return
+		}
+		w.Write([]byte(`{"_id":"d1","name":"cdn.example.com"}`))
+	}))
+	defer server.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	client := cachefly.NewClient(
+		cachefly.WithToken("t"),
+		cachefly.WithBaseURL(server.URL+"/api/2.6"),
+		cachefly.WithInstrumentation(New(
+			WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+			WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		)),
+	)
+
+	ctx := context.Background()
+	if _, err := client.ServiceDomains.GetByID(ctx, "svc-1", "d1", ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.ServiceDomains.GetByID(ctx, "svc-1", "missing", ""); err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(ended))
+	}
+	span := ended[0]
+	if span.Name() != "ServiceDomains.GetByID" || span.SpanKind() != trace.SpanKindClient {
+		t.Errorf("Unexpected span %s (%v)", span.Name(), span.SpanKind())
+	}
+	attrs := attribute.NewSet(span.Attributes()...)
+	for key, want := range map[attribute.Key]string{
+		"cachefly.service.id": "svc-1",
+		"cachefly.domain.id":  "d1",
+		AttrMethod:            "GET",
+		AttrPath:              "/services/svc-1/domains/d1",
+	} {
+		if v, _ := attrs.Value(key); v.AsString() != want {
+			t.Errorf("Expected %s=%s, got %q", key, want, v.Emit())
+		}
+	}
+	if v, _ := attrs.Value(AttrStatusCode); v.AsInt64() != 200 {
+		t.Errorf("Expected status code 200, got %v", v.Emit())
+	}
+	if ended[1].Status().Code != codes.Error || len(ended[1].Events()) != 1 {
+		t.Errorf("Expected the failed call to be recorded as an error, got %+v", ended[1].Status())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]int64{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		switch data := m.Data.(type) {
+		case metricdata.Sum[int64]:
+			for _, dp := range data.DataPoints {
+				got[m.Name] += dp.Value
+			}
+		case metricdata.Histogram[float64]:
+			for _, dp := range data.DataPoints {
+				got[m.Name] += int64(dp.Count)
+			}
+		}
+	}
+	if got["cachefly.client.requests"] != 2 || got["cachefly.client.errors"] != 1 || got["cachefly.client.duration"] != 2 {
+		t.Errorf("Unexpected metrics %v", got)
+	}
+}
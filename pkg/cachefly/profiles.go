@@ -0,0 +1,99 @@
+package cachefly
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// DefaultProfile is the profile used when none is selected.
+const DefaultProfile = "default"
+
+// Profile holds the connection settings stored under a name in a config file.
+type Profile struct {
+	Token   string `json:"token"`
+	BaseURL string `json:"baseUrl,omitempty"`
+}
+
+// ConfigFile is the file holding named profiles, shared by the cachefly CLI
+// and ProfileToken:
+//
+//	{
+//	  "current": "prod",
+//	  "profiles": {
+//	    "prod": {"token": "..."},
+//	    "staging": {"token": "...", "baseUrl": "https://staging.example.com/api/2.6"}
+//	  }
+//	}
+type ConfigFile struct {
+	Current  string             `json:"current,omitempty"`
+	Profiles map[string]Profile `json:"profiles"`
+}
+
+// DefaultConfigPath returns $CACHEFLY_CONFIG, or ~/.cachefly/config.
+func DefaultConfigPath() (string, error) {
+	if p := os.Getenv("CACHEFLY_CONFIG"); p != "" {
+		return p, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".cachefly", "config"), nil
+}
+
+// LoadConfigFile reads a config file. A missing file yields an empty config.
+func LoadConfigFile(path string) (*ConfigFile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &ConfigFile{Profiles: map[string]Profile{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	return parseConfigFile(path, data)
+}
+
+func parseConfigFile(path string, data []byte) (*ConfigFile, error) {
+	cfg := &ConfigFile{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]Profile{}
+	}
+	return cfg, nil
+}
+
+// Save writes the config file, readable only by its owner since it holds API
+// tokens.
+func (c *ConfigFile) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// Profile returns the named profile and the name it was resolved to. An
+// empty name selects $CACHEFLY_PROFILE, the current profile or
+// DefaultProfile, in that order.
+func (c *ConfigFile) Profile(name string) (Profile, string, bool) {
+	switch {
+	case name != "":
+	case os.Getenv("CACHEFLY_PROFILE") != "":
+		name = os.Getenv("CACHEFLY_PROFILE")
+	case c.Current != "":
+		name = c.Current
+	default:
+		name = DefaultProfile
+	}
+	p, ok := c.Profiles[name]
+	return p, name, ok
+}
@@ -0,0 +1,17 @@
+package cachefly
+
+import "github.com/cachefly/cachefly-sdk-go/internal/httpclient"
+
+// RetryPolicy configures automatic retries of failed API requests.
+// See WithRetryPolicy.
+type RetryPolicy = httpclient.RetryPolicy
+
+// RetryEvent describes a failed attempt that is about to be retried.
+// It is passed to RetryPolicy.OnRetry.
+type RetryEvent = httpclient.RetryEvent
+
+// DefaultRetryPolicy returns a policy suitable for most workloads: up to 4
+// attempts with exponential backoff from 500ms to 30s and 20% jitter.
+func DefaultRetryPolicy() RetryPolicy {
+	return httpclient.DefaultRetryPolicy()
+}
@@ -0,0 +1,108 @@
+package cachefly
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cachefly/cachefly-sdk-go/internal/httpclient"
+)
+
+// TokenProvider supplies the API token. It is consulted before every request,
+// so long-running programs can rotate tokens without creating a new Client.
+// See WithTokenProvider.
+type TokenProvider = httpclient.TokenProvider
+
+// TokenProviderFunc adapts an ordinary function to TokenProvider.
+type TokenProviderFunc = httpclient.TokenProviderFunc
+
+// StaticToken returns a TokenProvider that always returns token.
+func StaticToken(token string) TokenProvider {
+	return httpclient.StaticToken(token)
+}
+
+// EnvToken returns a TokenProvider that reads the environment variable name,
+// or CACHEFLY_API_TOKEN when name is empty, on every request.
+func EnvToken(name string) TokenProvider {
+	if name == "" {
+		name = "CACHEFLY_API_TOKEN"
+	}
+	return TokenProviderFunc(func(ctx context.Context) (string, error) {
+		if token := os.Getenv(name); token != "" {
+			return token, nil
+		}
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	})
+}
+
+// FileToken returns a TokenProvider that reads the token from a file,
+// ignoring surrounding whitespace. The file is read again whenever its
+// modification time or size changes, e.g. when a secret is rotated.
+func FileToken(path string) TokenProvider {
+	return &watchedFile{path: path, parse: func(data []byte) (string, error) {
+		token := string(bytes.TrimSpace(data))
+		if token == "" {
+			return "", fmt.Errorf("token file %s is empty", path)
+		}
+		return token, nil
+	}}
+}
+
+// ProfileToken returns a TokenProvider that reads the token of a profile from
+// the config file at path (see LoadConfigFile). When name is empty, the
+// profile is taken from $CACHEFLY_PROFILE, then the current profile of the
+// file, then "default". Like FileToken, the file is read again when it
+// changes.
+func ProfileToken(path, name string) TokenProvider {
+	return &watchedFile{path: path, parse: func(data []byte) (string, error) {
+		cfg, err := parseConfigFile(path, data)
+		if err != nil {
+			return "", err
+		}
+		p, resolved, ok := cfg.Profile(name)
+		if !ok {
+			return "", fmt.Errorf("profile %q not found in %s", resolved, path)
+		}
+		if p.Token == "" {
+			return "", fmt.Errorf("profile %q in %s has no token", resolved, path)
+		}
+		return p.Token, nil
+	}}
+}
+
+// watchedFile caches a value parsed from a file until the file changes.
+type watchedFile struct {
+	path  string
+	parse func([]byte) (string, error)
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	value   string
+}
+
+func (f *watchedFile) Token(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", err
+	}
+	if f.value != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.value, nil
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", err
+	}
+	value, err := f.parse(data)
+	if err != nil {
+		return "", err
+	}
+	f.value, f.modTime, f.size = value, info.ModTime(), info.Size()
+	return value, nil
+}
@@ -0,0 +1,81 @@
+package cachefly
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	os.WriteFile(path, []byte("first\n"), 0o600)
+	p := FileToken(path)
+
+	if got, err := p.Token(context.Background()); err != nil || got != "first" {
+		t.Fatalf("Expected first, got %q, %v", got, err)
+	}
+	os.WriteFile(path, []byte("second-token\n"), 0o600)
+	if got, _ := p.Token(context.Background()); got != "second-token" {
+		t.Errorf("Expected the rotated token, got %q", got)
+	}
+
+	os.WriteFile(path, []byte("  \n"), 0o600)
+	os.Chtimes(path, time.Now(), time.Now().Add(time.Minute))
+	if _, err := p.Token(context.Background()); err == nil {
+		t.Error("Expected an error for an empty token file")
+	}
+}
+
+func TestEnvToken(t *testing.T) {
+	t.Setenv("TEST_CACHEFLY_TOKEN", "")
+	p := EnvToken("TEST_CACHEFLY_TOKEN")
+	if _, err := p.Token(context.Background()); err == nil {
+		t.Error("Expected an error for an unset variable")
+	}
+	t.Setenv("TEST_CACHEFLY_TOKEN", "abc")
+	if got, _ := p.Token(context.Background()); got != "abc" {
+		t.Errorf("Expected abc, got %q", got)
+	}
+}
+
+func TestWithProfile(t *testing.T) {
+	var auth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "config")
+	t.Setenv("CACHEFLY_CONFIG", path)
+	t.Setenv("CACHEFLY_PROFILE", "")
+	cfg := &ConfigFile{Current: "prod", Profiles: map[string]Profile{
+		"prod":    {Token: "prod-token", BaseURL: server.URL},
+		"staging": {Token: "staging-token"},
+	}}
+	if err := cfg.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient(WithProfile(""))
+	client.Accounts.Get(context.Background(), "")
+
+	cfg.Profiles["prod"] = Profile{Token: "rotated-token", BaseURL: server.URL}
+	cfg.Save(path)
+	os.Chtimes(path, time.Now(), time.Now().Add(time.Minute))
+	client.Accounts.Get(context.Background(), "")
+
+	if len(auth) != 2 || auth[0] != "Bearer prod-token" || auth[1] != "Bearer rotated-token" {
+		t.Errorf("Expected the current profile's token to be rotated, got %v", auth)
+	}
+
+	p := ProfileToken(path, "missing")
+	if _, err := p.Token(context.Background()); err == nil {
+		t.Error("Expected an error for a missing profile")
+	}
+}
@@ -0,0 +1,12 @@
+package cachefly
+
+import "github.com/cachefly/cachefly-sdk-go/internal/httpclient"
+
+// Middleware wraps the transport used for every API request. It can inspect
+// or modify requests before calling next and responses after it returns.
+// See WithMiddleware.
+type Middleware = httpclient.Middleware
+
+// RoundTripperFunc adapts an ordinary function to http.RoundTripper, which
+// is convenient when writing Middleware.
+type RoundTripperFunc = httpclient.RoundTripperFunc